@@ -4,11 +4,14 @@ go 1.24.2
 
 require (
 	github.com/bmatcuk/doublestar/v4 v4.8.1
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/golangci/plugin-module-register v0.1.2
+	golang.org/x/mod v0.25.0
 	golang.org/x/tools v0.34.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	golang.org/x/mod v0.25.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
 )
@@ -7,36 +7,108 @@ import (
 	"go/types"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
-	"golang.org/x/tools/go/analysis/singlechecker"
 	"golang.org/x/tools/go/ast/inspector"
 )
 
-// a implements plugin for finding unused interface methods.
-var a = &analysis.Analyzer{
-	Name:     "unused_interface_methods",
-	Doc:      "Checks for unused interface methods",
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      run,
+// a implements plugin for finding unused interface methods. It loads the
+// configuration file of the current directory on its first run.
+var a = newAnalyzer(nil)
+
+// NewAnalyzer returns an analyzer using the configuration c instead of the configuration file,
+// for drivers embedding it like golangci-lint. Each analyzer has its own configuration.
+func NewAnalyzer(c *config.Config) *analysis.Analyzer {
+	return newAnalyzer(&settings{cfg: c})
+}
+
+// newAnalyzer returns an analyzer running with the settings,
+// or with the configuration file of the current directory if s is nil
+func newAnalyzer(s *settings) *analysis.Analyzer {
+	load := func() (*settings, error) { return s, nil }
+	if s == nil {
+		load = sync.OnceValues(loadSettings)
+	}
+	return &analysis.Analyzer{
+		Name:     "unused_interface_methods",
+		Doc:      "Checks for unused interface methods",
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			s, err := load()
+			if err != nil {
+				return nil, err
+			}
+			return s.run(pass)
+		},
+		ResultType: reflect.TypeOf((*packageUsage)(nil)),
+	}
+}
+
+// settings are the configuration of one analyzer instance and the drivers using it
+type settings struct {
+	cfg      *config.Config
+	basePath string // root of paths in ignore matching, the module root of each file when empty
+
+	// moduleScope holds import paths of all packages analyzed in module mode.
+	// Every package also tracks usage of interfaces declared in the imported module
+	// packages, so a method counts as used wherever it is called. It is nil in the
+	// go/analysis mode used by editors and go vet, where each package is reported alone.
+	moduleScope map[string]bool
+
+	// sites collects every site using a method for the subcommands explaining usage.
+	// Otherwise matching stops at the first use of a method.
+	sites bool
+}
+
+// loadSettings loads the configuration file of the current directory
+func loadSettings() (*settings, error) {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	return &settings{cfg: cfg}, nil
 }
 
 // methodInfo represents information about a method in an interface.
 type methodInfo struct {
+	pkgPath   string           // import path of the package declaring the interface
 	ifaceName string           // interface name
 	iface     *types.Interface // interface object
 	method    *types.Func      // method object
 	used      bool             // used flag
+	contract  bool             // implemented by external consumers, never reported
+	foreign   bool             // declared in an imported package, tracked for usage only
+}
+
+// qualifiedName returns the method name qualified with its package and interface
+func (info methodInfo) qualifiedName() string {
+	return info.pkgPath + "." + info.ifaceName + "." + info.method.Name()
 }
 
 // collectInterfaceMethods collects all explicit interface methods in the package.
-func collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
+func (s *settings) collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
 	ifaceMethods := make(map[*types.Func]methodInfo, 32) // Pre-allocate with reasonable capacity
 	pathCache := make(map[string]string)                 // Local cache for this analysis run
 
+	// Usage of interfaces from other module packages counts even in ignored packages
+	if s.moduleScope != nil {
+		s.collectImportedInterfaceMethods(pass, ifaceMethods)
+	}
+
+	pkgPath := pass.Pkg.Path()
+	if s.cfg.ShouldIgnorePackage(pkgPath) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Skipping package: %s\n", pkgPath)
+		}
+		return ifaceMethods
+	}
+
 	for _, file := range pass.Files {
 		filename := pass.Fset.Position(file.Pos()).Filename
 
@@ -45,22 +117,23 @@ func collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
 		if cached, ok := pathCache[filename]; ok {
 			relPath = cached
 		} else {
-			var err error
-			relPath, err = filepath.Rel(basePath, filename)
-			if err != nil {
-				relPath = filename
-			}
-			// Normalize path separators for consistency
-			relPath = strings.ReplaceAll(relPath, "\\", "/")
+			relPath = s.relativePath(filename)
 			pathCache[filename] = relPath
 		}
 
-		if cfg.ShouldIgnore(relPath) {
+		if s.cfg.ShouldIgnore(relPath) {
 			if verbose {
 				fmt.Fprintf(os.Stderr, "[DEBUG] Skipping file: %s\n", relPath)
 			}
 			continue
 		}
+		// Calls in generated files still count as usage
+		if !s.cfg.IncludeGenerated && ast.IsGenerated(file) {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] Skipping generated file: %s\n", relPath)
+			}
+			continue
+		}
 		if verbose {
 			fmt.Fprintf(os.Stderr, "[DEBUG] File: %s\n", relPath)
 		}
@@ -79,6 +152,18 @@ func collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
 				if obj == nil {
 					continue
 				}
+				if !s.cfg.ShouldReport(pkgPath, obj.Exported()) {
+					if verbose {
+						fmt.Fprintf(os.Stderr, "[DEBUG] Skipping interface %s in %s mode\n", tspec.Name.Name, s.cfg.Mode)
+					}
+					continue
+				}
+				if s.cfg.ShouldIgnoreInterface(pkgPath, tspec.Name.Name) {
+					if verbose {
+						fmt.Fprintf(os.Stderr, "[DEBUG] Skipping interface: %s.%s\n", pkgPath, tspec.Name.Name)
+					}
+					continue
+				}
 				named, ok := obj.Type().(*types.Named)
 				if !ok {
 					continue
@@ -88,16 +173,19 @@ func collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
 					continue
 				}
 
+				marked := hasContractMarker(gd, tspec)
 				for i := 0; i < ifaceType.NumExplicitMethods(); i++ {
 					m := ifaceType.ExplicitMethod(i)
-					if m == nil {
+					if m == nil || s.cfg.ShouldIgnoreMethod(pkgPath, tspec.Name.Name, m.Name()) {
 						continue
 					}
 					ifaceMethods[m] = methodInfo{
+						pkgPath:   pkgPath,
 						ifaceName: tspec.Name.Name,
 						iface:     ifaceType,
 						method:    m,
 						used:      false,
+						contract:  marked || s.cfg.IsContract(pkgPath, tspec.Name.Name, m.Name()),
 					}
 				}
 			}
@@ -107,28 +195,131 @@ func collectInterfaceMethods(pass *analysis.Pass) map[*types.Func]methodInfo {
 	return ifaceMethods
 }
 
+// relativePath returns the file path relative to basePath or to the root of
+// its module for ignore matching, so that it does not depend on the package patterns
+func (s *settings) relativePath(filename string) string {
+	base := s.basePath
+	if base == "" {
+		base = moduleRoot(filepath.Dir(filename))
+	}
+	if base == "" {
+		base, _ = os.Getwd()
+	}
+	relPath, err := filepath.Rel(base, filename)
+	if err != nil {
+		relPath = filename
+	}
+	// Normalize path separators for consistency
+	return strings.ReplaceAll(relPath, "\\", "/")
+}
+
+// usageRule names the rule that matched a use of an interface method
+type usageRule string
+
+const (
+	ruleDirect    usageRule = "direct selection"     // call or method value on the interface
+	ruleVariable  usageRule = "variable assignment"  // call on a variable assigned from the interface
+	ruleConcrete  usageRule = "concrete type"        // call on a type implementing the interface
+	ruleGeneric   usageRule = "generic instance"     // call on an instance of the generic interface
+	ruleStringer  usageRule = "fmt Stringer"         // value formatted by a fmt function
+	ruleEmbedding usageRule = "embedding"            // call promoted through an embedded field
+	ruleGRPC      usageRule = "gRPC registration"    // server registered with its Register function
+	rulePassed    usageRule = "passed to dependency" // value passed to a dependency calling the method
+)
+
+// usageKind describes how calls in a file count as usage
+type usageKind int
+
+const (
+	usageRegular usageKind = iota // calls count as usage
+	usageTest                     // calls count as test-only usage
+	usageIgnored                  // calls do not count as usage
+)
+
 // methodAnalyzer handles analysis of method usage in AST
 type methodAnalyzer struct {
-	pass           *analysis.Pass
-	ifaceMethods   map[*types.Func]methodInfo
-	usedMethods    map[*types.Func]bool
-	varAssignments map[string]string        // maps variable name to interface type name
-	concreteTypes  map[string][]string      // maps variable name to concrete type names that were assigned
-	methodsByName  map[string][]*types.Func // Cache methods by name for faster lookup
+	pass            *analysis.Pass
+	ifaceMethods    map[*types.Func]methodInfo
+	usedMethods     map[*types.Func]bool
+	testUsedMethods map[*types.Func]bool                    // methods used from test files
+	varAssignments  map[string]string                       // maps variable name to interface type name
+	concreteTypes   map[string][]string                     // maps variable name to concrete type names that were assigned
+	methodsByName   map[string][]*types.Func                // Cache methods by name for faster lookup
+	fileUsage       map[*token.File]usageKind               // Cache usage kind by file
+	inTest          bool                                    // current node is in a test file
+	pos             token.Pos                               // position of the current node
+	useSites        map[*types.Func]map[token.Pos]usageRule // distinct positions using each method, with the first matching rule
+	mocks           *mockDetector                           // recognizes calls on generated mocks
+	typeCache       map[string]types.Type                   // caches type lookups by name to avoid repeated searches
+	collectSites    bool                                    // record every site using a method, not only the first
+	settings        *settings
 }
 
 // newMethodAnalyzer creates a new method analyzer
-func newMethodAnalyzer(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo) *methodAnalyzer {
+func newMethodAnalyzer(s *settings, pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo) *methodAnalyzer {
 	return &methodAnalyzer{
-		pass:           pass,
-		ifaceMethods:   ifaceMethods,
-		usedMethods:    make(map[*types.Func]bool),
-		varAssignments: make(map[string]string),
-		concreteTypes:  make(map[string][]string),
-		methodsByName:  make(map[string][]*types.Func),
+		pass:            pass,
+		ifaceMethods:    ifaceMethods,
+		usedMethods:     make(map[*types.Func]bool),
+		testUsedMethods: make(map[*types.Func]bool),
+		varAssignments:  make(map[string]string),
+		concreteTypes:   make(map[string][]string),
+		methodsByName:   make(map[string][]*types.Func),
+		fileUsage:       make(map[*token.File]usageKind),
+		useSites:        make(map[*types.Func]map[token.Pos]usageRule),
+		mocks:           newMockDetector(pass.Fset),
+		typeCache:       make(map[string]types.Type),
+		collectSites:    s.sites,
+		settings:        s,
 	}
 }
 
+// usageKindOf returns how calls at the given position count as usage
+func (ma *methodAnalyzer) usageKindOf(pos token.Pos) usageKind {
+	file := ma.pass.Fset.File(pos)
+	if file == nil {
+		return usageRegular
+	}
+	if kind, cached := ma.fileUsage[file]; cached {
+		return kind
+	}
+
+	kind := usageRegular
+	relPath := ma.settings.relativePath(file.Name())
+	switch {
+	case ma.settings.cfg.ShouldIgnoreUsage(relPath):
+		kind = usageIgnored
+	case ma.settings.cfg.IsTestFile(relPath):
+		kind = usageTest
+	}
+	ma.fileUsage[file] = kind
+	return kind
+}
+
+// markUsed records usage of an interface method at the current node by the rule,
+// separately for test files
+func (ma *methodAnalyzer) markUsed(method *types.Func, rule usageRule) {
+	if ma.collectSites {
+		if ma.useSites[method] == nil {
+			ma.useSites[method] = make(map[token.Pos]usageRule)
+		}
+		if _, ok := ma.useSites[method][ma.pos]; !ok {
+			ma.useSites[method][ma.pos] = rule
+		}
+	}
+	if ma.inTest {
+		ma.testUsedMethods[method] = true
+		return
+	}
+	ma.usedMethods[method] = true
+}
+
+// alreadyUsed checks if the method is used from regular files,
+// so that matching it again only collects sites
+func (ma *methodAnalyzer) alreadyUsed(method *types.Func) bool {
+	return ma.usedMethods[method] && !ma.collectSites
+}
+
 // getMethodsByName returns methods with the given name, building cache lazily
 func (ma *methodAnalyzer) getMethodsByName(name string) []*types.Func {
 	if methods, cached := ma.methodsByName[name]; cached {
@@ -147,14 +338,15 @@ func (ma *methodAnalyzer) getMethodsByName(name string) []*types.Func {
 	return methods
 }
 
-// analyzeUsedMethods traverses AST and marks used methods
-func analyzeUsedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo) map[*types.Func]bool {
-	methodAnalyzer := newMethodAnalyzer(pass, ifaceMethods)
+// analyzeUsedMethods traverses AST and marks used methods, returning methods used
+// from regular files, methods used from test files and the sites using each method
+func (s *settings) analyzeUsedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo) (used, testUsed map[*types.Func]bool, sites map[*types.Func]map[token.Pos]usageRule) {
+	methodAnalyzer := newMethodAnalyzer(s, pass, ifaceMethods)
 	return methodAnalyzer.analyze()
 }
 
 // analyze performs the main analysis logic
-func (ma *methodAnalyzer) analyze() map[*types.Func]bool {
+func (ma *methodAnalyzer) analyze() (used, testUsed map[*types.Func]bool, sites map[*types.Func]map[token.Pos]usageRule) {
 	ins := ma.pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 
 	// Single pass analysis combining both variable collection and method usage
@@ -165,6 +357,13 @@ func (ma *methodAnalyzer) analyze() map[*types.Func]bool {
 	}
 
 	ins.Preorder(nodeFilter, func(n ast.Node) {
+		kind := ma.usageKindOf(n.Pos())
+		if kind == usageIgnored {
+			return
+		}
+		ma.inTest = kind == usageTest
+		ma.pos = n.Pos()
+
 		switch node := n.(type) {
 		case *ast.GenDecl:
 			ma.analyzeGenDecl(node)
@@ -175,7 +374,11 @@ func (ma *methodAnalyzer) analyze() map[*types.Func]bool {
 		}
 	})
 
-	return ma.usedMethods
+	ma.inTest = false
+	ma.pos = token.NoPos
+	ma.markRegisteredServers()
+
+	return ma.usedMethods, ma.testUsedMethods, ma.useSites
 }
 
 // analyzeGenDecl handles variable declarations - replaces collectVarAssignments
@@ -226,7 +429,8 @@ func (ma *methodAnalyzer) analyzeGenDecl(stmt *ast.GenDecl) {
 				// Get the underlying type (without pointer)
 				if ptr, ok := rhsType.(*types.Pointer); ok {
 					elemType := ptr.Elem()
-					if named, ok := elemType.(*types.Named); ok {
+					// Mocks are not implementations
+					if named, ok := elemType.(*types.Named); ok && !ma.mocks.isMock(named) {
 						typeName := named.Obj().Name()
 						// Avoid slice allocation if possible
 						if ma.concreteTypes[lhsName] == nil {
@@ -245,17 +449,29 @@ func (ma *methodAnalyzer) analyzeGenDecl(stmt *ast.GenDecl) {
 	}
 }
 
-// analyzeSelectorExpr handles method calls through selectors
+// analyzeSelectorExpr handles method calls and method values through selectors
 func (ma *methodAnalyzer) analyzeSelectorExpr(node *ast.SelectorExpr) {
 	sel := ma.pass.TypesInfo.Selections[node]
-	if sel == nil || (sel.Kind() != types.MethodVal && sel.Kind() != types.MethodExpr) {
+	if sel == nil {
+		return
+	}
+	if sel.Kind() != types.MethodVal && sel.Kind() != types.MethodExpr {
+		return
+	}
+	// Expectation setups and calls on mocks are not usage
+	if ma.mocks.isMock(sel.Recv()) {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Skipping call on mock: %s.%s\n", sel.Recv(), sel.Obj().Name())
+		}
+		return
+	}
+	if sel.Kind() == types.MethodExpr {
+		ma.analyzeMethodExpr(sel)
 		return
 	}
 
 	calledMethod := sel.Obj().(*types.Func)
-	recv := sel.Recv()
-
-	ma.markMatchingMethods(calledMethod, recv)
+	ma.markSelectedMethods(calledMethod, sel)
 
 	// Also check if receiver is a variable that was assigned from another interface
 	ident, isIdent := node.X.(*ast.Ident)
@@ -275,13 +491,13 @@ func (ma *methodAnalyzer) analyzeSelectorExpr(node *ast.SelectorExpr) {
 	// Check variable assignments
 	if sourceType, found := ma.varAssignments[identName]; found {
 		for _, ifaceMethod := range candidates {
-			if ma.usedMethods[ifaceMethod] {
+			if ma.alreadyUsed(ifaceMethod) {
 				continue
 			}
 			info := ma.ifaceMethods[ifaceMethod]
 			if info.ifaceName == sourceType &&
 				types.Identical(ifaceMethod.Type(), calledMethod.Type()) {
-				ma.usedMethods[ifaceMethod] = true
+				ma.markUsed(ifaceMethod, ruleVariable)
 				if verbose {
 					fmt.Fprintf(os.Stderr, "[DEBUG] Marking %s.%s as used (from variable assignment)\n",
 						sourceType, ifaceMethod.Name())
@@ -293,7 +509,7 @@ func (ma *methodAnalyzer) analyzeSelectorExpr(node *ast.SelectorExpr) {
 	// Check concrete type assignments
 	if concreteTypes, found := ma.concreteTypes[identName]; found {
 		for _, ifaceMethod := range candidates {
-			if ma.usedMethods[ifaceMethod] {
+			if ma.alreadyUsed(ifaceMethod) {
 				continue
 			}
 			if !types.Identical(ifaceMethod.Type(), calledMethod.Type()) {
@@ -304,7 +520,7 @@ func (ma *methodAnalyzer) analyzeSelectorExpr(node *ast.SelectorExpr) {
 			// For each concrete type that was assigned to this variable
 			for _, typeName := range concreteTypes {
 				if ma.concreteTypeImplementsInterface(typeName, info.iface) {
-					ma.usedMethods[ifaceMethod] = true
+					ma.markUsed(ifaceMethod, ruleConcrete)
 					if verbose {
 						fmt.Fprintf(os.Stderr, "[DEBUG] Marking %s.%s as used (concrete type %s implements it)\n",
 							info.ifaceName, ifaceMethod.Name(), typeName)
@@ -316,8 +532,62 @@ func (ma *methodAnalyzer) analyzeSelectorExpr(node *ast.SelectorExpr) {
 	}
 }
 
-// markMatchingMethods marks interface methods that match the called method
-func (ma *methodAnalyzer) markMatchingMethods(calledMethod *types.Func, recv types.Type) {
+// analyzeMethodExpr handles method expressions like Reader.Read or (*T).Method.
+// An expression on an interface type yields a function that dispatches through
+// the interface, so it counts as usage wherever it ends up (variable, map, struct
+// field, return value). An expression on a concrete type counts like a method
+// value on that type: for the interfaces the type implements.
+func (ma *methodAnalyzer) analyzeMethodExpr(sel *types.Selection) {
+	calledMethod := sel.Obj().(*types.Func)
+	if verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Method expression: %s.%s\n", sel.Recv(), calledMethod.Name())
+	}
+	ma.markSelectedMethods(calledMethod, sel)
+}
+
+// markSelectedMethods marks interface methods matching a method value or
+// expression on the selection receiver and on the embedded fields it goes through
+func (ma *methodAnalyzer) markSelectedMethods(calledMethod *types.Func, sel *types.Selection) {
+	ma.markMatchingMethods(calledMethod, sel.Recv(), len(sel.Index()) > 1)
+	ma.markPromotedMethods(calledMethod, sel)
+}
+
+// markPromotedMethods marks interface methods reached through embedded fields.
+// For a selection like s.Get() where s embeds Store (possibly several levels
+// deep or through pointers), each embedded field type along sel.Index() is
+// treated as a receiver of its own.
+func (ma *methodAnalyzer) markPromotedMethods(calledMethod *types.Func, sel *types.Selection) {
+	for _, recv := range embeddedFieldTypes(sel) {
+		ma.markMatchingMethods(calledMethod, recv, true)
+	}
+}
+
+// embeddedFieldTypes returns types of embedded fields traversed by the selection
+func embeddedFieldTypes(sel *types.Selection) []types.Type {
+	path := sel.Index()
+	if len(path) < 2 {
+		return nil
+	}
+
+	var result []types.Type
+	t := sel.Recv()
+	for _, idx := range path[:len(path)-1] {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || idx >= st.NumFields() {
+			break
+		}
+		t = st.Field(idx).Type()
+		result = append(result, t)
+	}
+	return result
+}
+
+// markMatchingMethods marks interface methods that match the called method,
+// promoted through an embedded field of type recv if promoted is set
+func (ma *methodAnalyzer) markMatchingMethods(calledMethod *types.Func, recv types.Type, promoted bool) {
 	// First, check only methods with matching names
 	calledName := calledMethod.Name()
 	candidates := ma.getMethodsByName(calledName)
@@ -326,34 +596,37 @@ func (ma *methodAnalyzer) markMatchingMethods(calledMethod *types.Func, recv typ
 	}
 
 	for _, ifaceMethod := range candidates {
-		if ma.usedMethods[ifaceMethod] {
+		if ma.alreadyUsed(ifaceMethod) {
 			continue
 		}
 
 		info := ma.ifaceMethods[ifaceMethod]
-		if ma.isMethodMatch(calledMethod, ifaceMethod, recv, info) {
-			ma.usedMethods[ifaceMethod] = true
+		if rule, ok := ma.matchRule(calledMethod, ifaceMethod, recv, info); ok {
+			if promoted {
+				rule = ruleEmbedding
+			}
+			ma.markUsed(ifaceMethod, rule)
 		}
 	}
 }
 
-// isMethodMatch checks if called method matches interface method
-func (ma *methodAnalyzer) isMethodMatch(calledMethod, ifaceMethod *types.Func, recv types.Type, info methodInfo) bool {
+// matchRule checks if called method matches interface method, returning the matching rule
+func (ma *methodAnalyzer) matchRule(calledMethod, ifaceMethod *types.Func, recv types.Type, info methodInfo) (usageRule, bool) {
 	// Handle nil receiver
 	if recv == nil {
 		// For nil receiver, we can only match by name and signature
-		return calledMethod.Name() == ifaceMethod.Name() &&
+		return ruleDirect, calledMethod.Name() == ifaceMethod.Name() &&
 			types.Identical(calledMethod.Type(), ifaceMethod.Type())
 	}
 
 	// direct match - this is the most reliable way
 	if calledMethod == ifaceMethod {
-		return true
+		return ruleDirect, true
 	}
 
 	// For any other match, we need exact name AND signature match
 	if calledMethod.Name() != ifaceMethod.Name() {
-		return false
+		return "", false
 	}
 
 	// For generic interfaces, we need to handle instantiated types
@@ -367,7 +640,7 @@ func (ma *methodAnalyzer) isMethodMatch(calledMethod, ifaceMethod *types.Func, r
 				// This is an instantiation of our interface
 				// We need to check if the method signatures match after substitution
 				if ma.genericMethodsMatch(calledMethod, ifaceMethod, named, origin) {
-					return true
+					return ruleGeneric, true
 				}
 			}
 		}
@@ -375,17 +648,17 @@ func (ma *methodAnalyzer) isMethodMatch(calledMethod, ifaceMethod *types.Func, r
 
 	// Signature must be identical (for non-generic cases)
 	if !types.Identical(calledMethod.Type(), ifaceMethod.Type()) {
-		return false
+		return "", false
 	}
 
 	// Now check if the call is actually on this interface
 	// For interface receivers, require exact match
 	if _, isIface := recv.Underlying().(*types.Interface); isIface {
-		return types.Identical(recv, info.iface)
+		return ruleDirect, types.Identical(recv, info.iface)
 	}
 
 	// For concrete receivers, check if they implement this specific interface
-	return types.Implements(recv, info.iface)
+	return ruleConcrete, types.Implements(recv, info.iface)
 }
 
 // genericMethodsMatch checks if methods match considering generic type parameters
@@ -410,6 +683,11 @@ func (ma *methodAnalyzer) genericMethodsMatch(instMethod, genericMethod *types.F
 
 // analyzeCallExpr handles function calls (specifically fmt.* functions)
 func (ma *methodAnalyzer) analyzeCallExpr(node *ast.CallExpr) {
+	// Facts of dependencies are available only when run by go vet
+	if len(ma.pass.Analyzer.FactTypes) > 0 {
+		ma.markPassedMethods(node)
+	}
+
 	ident := ma.extractFunctionIdent(node)
 	if ident == nil {
 		return
@@ -444,7 +722,7 @@ func (ma *methodAnalyzer) isFmtFunction(ident *ast.Ident) bool {
 func (ma *methodAnalyzer) analyzeFmtCall(node *ast.CallExpr) {
 	for _, arg := range node.Args {
 		argType := ma.pass.TypesInfo.TypeOf(arg)
-		if argType == nil {
+		if argType == nil || ma.mocks.isMock(argType) {
 			continue
 		}
 
@@ -461,7 +739,7 @@ func (ma *methodAnalyzer) checkStringerUsage(argType types.Type) {
 	}
 
 	for _, ifaceMethod := range stringMethods {
-		if ma.usedMethods[ifaceMethod] {
+		if ma.alreadyUsed(ifaceMethod) {
 			continue
 		}
 
@@ -475,7 +753,7 @@ func (ma *methodAnalyzer) checkStringerUsage(argType types.Type) {
 			continue
 		}
 		if types.Implements(argType, info.iface) {
-			ma.usedMethods[ifaceMethod] = true
+			ma.markUsed(ifaceMethod, ruleStringer)
 		}
 	}
 }
@@ -496,7 +774,8 @@ func (ma *methodAnalyzer) isStringerMethod(method *types.Func) bool {
 }
 
 // reportUnusedMethods sorts and reports methods that were not used.
-func reportUnusedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo, used map[*types.Func]bool) {
+// Methods used only from test files are reported with a separate message.
+func (s *settings) reportUnusedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo, used, testUsed map[*types.Func]bool) {
 	// mark used methods
 	for m := range used {
 		if info, ok := ifaceMethods[m]; ok {
@@ -507,7 +786,7 @@ func reportUnusedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]metho
 
 	var unused []methodInfo
 	for _, info := range ifaceMethods {
-		if !info.used {
+		if !info.used && !info.contract && !info.foreign {
 			unused = append(unused, info)
 		}
 	}
@@ -522,19 +801,50 @@ func reportUnusedMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]metho
 	})
 
 	for _, info := range unused {
-		pass.Reportf(info.method.Pos(), "method %q of interface %q is declared but not used", info.method.Name(), info.ifaceName)
+		if diag, ok := s.unusedDiagnostic(info, testUsed[info.method]); ok {
+			pass.Report(diag)
+		}
 	}
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	ifaceMethods := collectInterfaceMethods(pass)
-	used := analyzeUsedMethods(pass, ifaceMethods)
-	reportUnusedMethods(pass, ifaceMethods, used)
-	return nil, nil
+// unusedDiagnostic builds the diagnostic for an unused or test-only method,
+// returning false if its severity is off
+func (s *settings) unusedDiagnostic(info methodInfo, testOnly bool) (analysis.Diagnostic, bool) {
+	severity, message := s.unusedMessage(info.pkgPath, info.ifaceName, info.method.Name(), testOnly)
+	if severity == config.SeverityOff {
+		return analysis.Diagnostic{}, false
+	}
+	return analysis.Diagnostic{
+		Pos:      info.method.Pos(),
+		End:      info.method.Pos() + token.Pos(len(info.method.Name())),
+		Category: string(severity),
+		Message:  message,
+	}, true
 }
 
-func Run() {
-	singlechecker.Main(a)
+// unusedMessage returns the configured severity and the message for an unused or test-only method
+func (s *settings) unusedMessage(pkgPath, ifaceName, methodName string, testOnly bool) (config.Severity, string) {
+	exported := token.IsExported(ifaceName)
+	if testOnly {
+		return s.cfg.TestOnlySeverityFor(pkgPath, ifaceName, exported),
+			fmt.Sprintf("method %q of interface %q is only used from tests", methodName, ifaceName)
+	}
+	return s.cfg.SeverityFor(pkgPath, ifaceName, exported),
+		fmt.Sprintf("method %q of interface %q is declared but not used", methodName, ifaceName)
+}
+
+// run analyzes the package with the settings
+func (s *settings) run(pass *analysis.Pass) (interface{}, error) {
+	ifaceMethods := s.collectInterfaceMethods(pass)
+	used, testUsed, sites := s.analyzeUsedMethods(pass, ifaceMethods)
+	if len(pass.Analyzer.FactTypes) > 0 {
+		exportCalledFacts(pass, ifaceMethods, used, testUsed)
+	}
+	// In module mode the driver reports from the results of all packages
+	if s.moduleScope == nil {
+		s.reportUnusedMethods(pass, ifaceMethods, used, testUsed)
+	}
+	return s.newPackageUsage(pass.Fset, ifaceMethods, used, testUsed, sites), nil
 }
 
 // getTypeName extracts the name of a named type
@@ -545,13 +855,10 @@ func getTypeName(t types.Type) string {
 	return ""
 }
 
-// typeCache caches type lookups to avoid repeated searches
-var typeCache = make(map[string]types.Type)
-
 // concreteTypeImplementsInterface checks if a concrete type implements an interface
 func (ma *methodAnalyzer) concreteTypeImplementsInterface(typeName string, iface *types.Interface) bool {
 	// Check cache first
-	if cachedType, found := typeCache[typeName]; found {
+	if cachedType, found := ma.typeCache[typeName]; found {
 		if named, ok := cachedType.(*types.Named); ok {
 			return types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface)
 		}
@@ -566,7 +873,7 @@ func (ma *methodAnalyzer) concreteTypeImplementsInterface(typeName string, iface
 		if obj.Name() == typeName {
 			if named, ok := obj.Type().(*types.Named); ok {
 				// Cache the type for future lookups
-				typeCache[typeName] = named
+				ma.typeCache[typeName] = named
 				// Check both pointer and non-pointer receivers
 				if types.Implements(named, iface) || types.Implements(types.NewPointer(named), iface) {
 					return true
@@ -1,8 +1,10 @@
 package analizer
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -14,3 +16,64 @@ func TestAnalyzer(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, a, "test")
 }
+
+func TestAnalyzerSeverity(t *testing.T) {
+	unexported := false
+	analyzer := NewAnalyzer(&config.Config{
+		SeverityRules: []config.SeverityRule{
+			{Interfaces: []string{"*Handler"}, Level: config.SeverityInfo},
+			{Exported: &unexported, Level: config.SeverityOff},
+		},
+	})
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, analyzer, "severity")
+
+	categories := make(map[string]string)
+	for _, result := range results {
+		for _, diag := range result.Diagnostics {
+			categories[diag.Message] = diag.Category
+		}
+	}
+	want := map[string]string{
+		`method "Put" of interface "Store" is declared but not used`:           "error",
+		`method "Handle" of interface "EventHandler" is declared but not used`: "info",
+	}
+	if !reflect.DeepEqual(categories, want) {
+		t.Errorf("diagnostic categories = %v, want %v", categories, want)
+	}
+}
+
+func TestAnalyzerMode(t *testing.T) {
+	analyzer := NewAnalyzer(&config.Config{Mode: config.ModeUnexported})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer, "modes")
+}
+
+func TestAnalyzerIgnoreQualifiedNames(t *testing.T) {
+	analyzer := NewAnalyzer(&config.Config{
+		IgnoreInterfaces: []string{"qualified.Plugin", "qualified.Store.Put"},
+		IgnorePackages:   []string{"qualified/gen/..."},
+	})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer, "qualified", "qualified/gen")
+}
+
+func TestAnalyzerMocks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "mocking")
+}
+
+func TestAnalyzerGenerated(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "generated")
+}
+
+func TestAnalyzerIncludeGenerated(t *testing.T) {
+	analyzer := NewAnalyzer(&config.Config{IncludeGenerated: true})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer, "includegenerated")
+}
@@ -35,6 +35,7 @@ type Interface3 interface {
 }
 `
 
+	s := defaultSettings(b)
 	for i := 0; i < b.N; i++ {
 		fset := token.NewFileSet()
 		file, _ := parser.ParseFile(fset, "test.go", code, 0)
@@ -54,7 +55,7 @@ type Interface3 interface {
 			TypesInfo: info,
 		}
 
-		collectInterfaceMethods(pass)
+		s.collectInterfaceMethods(pass)
 	}
 }
 
@@ -96,17 +97,19 @@ func useInterface() {
 		Files:     []*ast.File{file},
 		Pkg:       pkg,
 		TypesInfo: info,
+		Analyzer:  a,
 		ResultOf:  make(map[*analysis.Analyzer]interface{}),
 	}
 
 	// Add inspector result
 	pass.ResultOf[inspect.Analyzer] = inspector.New([]*ast.File{file})
 
-	ifaceMethods := collectInterfaceMethods(pass)
+	s := defaultSettings(b)
+	ifaceMethods := s.collectInterfaceMethods(pass)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		analyzeUsedMethods(pass, ifaceMethods)
+		s.analyzeUsedMethods(pass, ifaceMethods)
 	}
 }
 
@@ -145,7 +148,7 @@ func BenchmarkMarkMatchingMethods(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		ma.usedMethods = make(map[*types.Func]bool) // Reset for each iteration
-		ma.markMatchingMethods(testMethod, nil)
+		ma.markMatchingMethods(testMethod, nil, false)
 	}
 }
 
@@ -179,16 +182,18 @@ func benchmarkTestdataFile(b *testing.B, filename string) {
 		Files:     []*ast.File{file},
 		Pkg:       pkg,
 		TypesInfo: info,
+		Analyzer:  a,
 		ResultOf:  make(map[*analysis.Analyzer]interface{}),
 	}
 
 	// Add inspector result
 	pass.ResultOf[inspect.Analyzer] = inspector.New([]*ast.File{file})
 
+	s := defaultSettings(b)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ifaceMethods := collectInterfaceMethods(pass)
-		analyzeUsedMethods(pass, ifaceMethods)
+		ifaceMethods := s.collectInterfaceMethods(pass)
+		s.analyzeUsedMethods(pass, ifaceMethods)
 	}
 }
 
@@ -204,11 +209,21 @@ func BenchmarkTestdataGenerics(b *testing.B) {
 	benchmarkTestdataFile(b, "testdata/src/test/generics.go")
 }
 
+func BenchmarkTestdataEmbedding(b *testing.B) {
+	benchmarkTestdataFile(b, "testdata/src/test/embedding.go")
+}
+
+func BenchmarkTestdataMethodExpr(b *testing.B) {
+	benchmarkTestdataFile(b, "testdata/src/test/methodexpr.go")
+}
+
 func BenchmarkTestdataAll(b *testing.B) {
 	files := []string{
 		"testdata/src/test/interfaces.go",
 		"testdata/src/test/reflection.go",
 		"testdata/src/test/generics.go",
+		"testdata/src/test/embedding.go",
+		"testdata/src/test/methodexpr.go",
 	}
 
 	fset := token.NewFileSet()
@@ -244,15 +259,17 @@ func BenchmarkTestdataAll(b *testing.B) {
 		Files:     astFiles,
 		Pkg:       pkg,
 		TypesInfo: info,
+		Analyzer:  a,
 		ResultOf:  make(map[*analysis.Analyzer]interface{}),
 	}
 
 	// Add inspector result
 	pass.ResultOf[inspect.Analyzer] = inspector.New(astFiles)
 
+	s := defaultSettings(b)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ifaceMethods := collectInterfaceMethods(pass)
-		analyzeUsedMethods(pass, ifaceMethods)
+		ifaceMethods := s.collectInterfaceMethods(pass)
+		s.analyzeUsedMethods(pass, ifaceMethods)
 	}
 }
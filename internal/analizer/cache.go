@@ -0,0 +1,244 @@
+package analizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/packages"
+)
+
+// cacheVersion changes whenever the format of cache entries or their key changes
+const cacheVersion = "4"
+
+const (
+	cacheMaxAge       = 5 * 24 * time.Hour // entries unused for longer are removed
+	cacheTrimInterval = 24 * time.Hour     // how often unused entries are looked for
+	cacheTouchAge     = time.Hour          // reused entries older than this get a new modification time
+)
+
+// analysisCache stores the usage of analyzed packages on disk, keyed by a hash of
+// everything the result depends on: the package files, the keys of all its
+// dependencies, the configuration, the flags and the tool itself.
+// Keys are computed from the package list alone, so packages found in the cache
+// are neither parsed nor type-checked. Editing a package changes its key and the
+// keys of all packages depending on it. Like the Go build cache, entries are
+// removed once they have not been used for cacheMaxAge.
+type analysisCache struct {
+	dir          string
+	salt         []byte            // hash of the tool, configuration and flags
+	files        map[string][]byte // content hashes by file name
+	hits, misses int
+}
+
+// cacheDir returns the cache directory, under $XDG_CACHE_HOME on Linux
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "unused-interface-methods"), nil
+}
+
+// openCache opens the cache for a run with the configuration and the given flags
+func openCache(cfg *config.Config, flags string) (*analysisCache, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version %s\n%s\n", cacheVersion, flags)
+	if err := hashExecutable(h); err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(h).Encode(cfg); err != nil {
+		return nil, err
+	}
+	c := &analysisCache{
+		dir:   dir,
+		salt:  h.Sum(nil),
+		files: make(map[string][]byte),
+	}
+	c.trim(time.Now())
+	return c, nil
+}
+
+// hashExecutable writes the content of the running executable, so that
+// results of another build of the tool are never reused
+func hashExecutable(h hash.Hash) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// keys returns the cache keys of the listed packages analyzed with the module scope.
+// The packages need their files and all their dependencies, see listPackagesIn.
+func (c *analysisCache) keys(pkgs []*packages.Package, moduleScope map[string]bool) map[*packages.Package]string {
+	hashes := make(map[*packages.Package][]byte)
+	keys := make(map[*packages.Package]string, len(pkgs))
+	for _, pkg := range pkgs {
+		h := sha256.New()
+		h.Write(c.salt)
+		h.Write(c.packageHash(pkg, moduleScope, hashes))
+		keys[pkg] = hex.EncodeToString(h.Sum(nil))
+	}
+	return keys
+}
+
+// packageHash returns the hash of the package files, whether the package is in
+// the module scope and the hashes of its imports, memoized in hashes
+func (c *analysisCache) packageHash(pkg *packages.Package, moduleScope map[string]bool, hashes map[*packages.Package][]byte) []byte {
+	if sum, ok := hashes[pkg]; ok {
+		return sum
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "package %s %t\n", pkg.ID, moduleScope[pkg.PkgPath])
+	for _, files := range [][]string{pkg.GoFiles, pkg.OtherFiles, pkg.EmbedFiles} {
+		for _, filename := range files {
+			fmt.Fprintf(h, "file %s %x\n", filename, c.fileHash(filename))
+		}
+	}
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(h, "import %s %x\n", path, c.packageHash(pkg.Imports[path], moduleScope, hashes))
+	}
+	sum := h.Sum(nil)
+	hashes[pkg] = sum
+	return sum
+}
+
+// fileHash returns the hash of the file content, or nil if it cannot be read
+func (c *analysisCache) fileHash(filename string) []byte {
+	if sum, ok := c.files[filename]; ok {
+		return sum
+	}
+	sum := hashFile(filename)
+	c.files[filename] = sum
+	return sum
+}
+
+// hashFile returns the hash of the file content, or nil if it cannot be read
+func hashFile(filename string) []byte {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil
+	}
+	return h.Sum(nil)
+}
+
+// get returns the cached usage of the package with the key
+func (c *analysisCache) get(key string) (*packageUsage, bool) {
+	filename := filepath.Join(c.dir, key+".json")
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		c.misses++
+		return nil, false
+	}
+	var usage packageUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	touch(filename, time.Now())
+	return &usage, true
+}
+
+// touch updates the modification time of a reused entry, which trim reads as
+// the time of last use. Recent entries are left alone to save writes.
+func touch(filename string, now time.Time) {
+	if info, err := os.Stat(filename); err == nil && now.Sub(info.ModTime()) > cacheTouchAge {
+		os.Chtimes(filename, now, now)
+	}
+}
+
+// trim removes entries and leftover temporary files unused for cacheMaxAge.
+// It runs at most once per cacheTrimInterval, recorded by the modification
+// time of the trim.txt file in the cache directory.
+func (c *analysisCache) trim(now time.Time) {
+	stamp := filepath.Join(c.dir, "trim.txt")
+	if info, err := os.Stat(stamp); err == nil && now.Sub(info.ModTime()) < cacheTrimInterval {
+		return
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" && filepath.Ext(entry.Name()) != ".tmp" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && now.Sub(info.ModTime()) > cacheMaxAge {
+			os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+	if err := os.WriteFile(stamp, nil, 0o644); err == nil {
+		os.Chtimes(stamp, now, now)
+	}
+}
+
+// put stores the usage of the package with the key, ignoring write errors
+func (c *analysisCache) put(key string, usage *packageUsage) {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return
+	}
+	// Write to a temporary file first, so concurrent runs never read partial entries
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		if verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Cache write failed: %v\n", err)
+		}
+	}
+}
+
+// printStats prints the cache hit rate
+func (c *analysisCache) printStats(w io.Writer) {
+	total := c.hits + c.misses
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(w, "[DEBUG] Cache: %d of %d packages reused (%.1f%% hit rate)\n",
+		c.hits, total, 100*float64(c.hits)/float64(total))
+}
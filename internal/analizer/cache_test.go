@@ -0,0 +1,121 @@
+package analizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalysisCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	isolateGoEnv(t)
+	root := writeModule(t, map[string]string{
+		"go.mod":         "module example.com/c\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/c/store\"\n\nfunc Use(s store.Store) string { return s.Get() }\n",
+		"other/other.go": "package other\n\ntype Closer interface {\n\tClose()\n}\n",
+	})
+	s := defaultSettings(t)
+	s.moduleScope = make(map[string]bool)
+	run := func(name string, hits, misses int) []finding {
+		t.Helper()
+		cache, err := openCache(s.cfg, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := s.loadAndAnalyze(root, []string{"./..."}, false, "", cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cache.hits != hits || cache.misses != misses {
+			t.Errorf("%s: %d hits, %d misses, want %d hits, %d misses", name, cache.hits, cache.misses, hits, misses)
+		}
+		var buf bytes.Buffer
+		findings, _ := s.moduleFindings(&buf, results)
+		return findings
+	}
+
+	want := run("first run", 0, 3)
+	if len(want) != 2 {
+		t.Fatalf("first run: %d findings, want 2", len(want))
+	}
+	if got := run("second run", 3, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("cached findings = %v, want %v", got, want)
+	}
+
+	// Editing a package invalidates it and the packages importing it
+	writeFiles(t, root, map[string]string{
+		"store/store.go": "package store\n\n// Store stores.\ntype Store interface {\n\tGet() string\n\tPut()\n}\n",
+	})
+	got := run("run after an edit", 1, 2)
+	if len(got) != 2 || got[0] != want[0] || got[1].Posn.Line != want[1].Posn.Line+1 {
+		t.Errorf("findings after an edit = %v, want the store finding one line lower than in %v", got, want)
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(entries) != 5 {
+		t.Errorf("cache has %d entries, want 5", len(entries))
+	}
+
+	cache, err := openCache(s.cfg, "other flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := listPackagesIn(root, []string{"./..."}, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pkg, key := range cache.keys(pkgs, s.moduleScope) {
+		if _, ok := cache.get(key); ok {
+			t.Errorf("package %s reused with other settings", pkg.ID)
+		}
+	}
+}
+
+func TestAnalysisCacheTrim(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old := now.Add(-cacheMaxAge - time.Hour)
+	writeEntry := func(name string, mtime time.Time) {
+		t.Helper()
+		writeFiles(t, dir, map[string]string{name: "{}"})
+		if err := os.Chtimes(filepath.Join(dir, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeEntry("old.json", old)
+	writeEntry("old.123.tmp", old)
+	writeEntry("fresh.json", now)
+
+	if _, err := openCache(defaultSettings(t).cfg, "test"); err != nil {
+		t.Fatal(err)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	for name, want := range map[string]bool{"old.json": false, "old.123.tmp": false, "fresh.json": true, "trim.txt": true} {
+		if got := exists(name); got != want {
+			t.Errorf("after trimming, %s exists = %t, want %t", name, got, want)
+		}
+	}
+
+	// Unused entries are looked for at most once per trim interval
+	writeEntry("old.json", old)
+	if _, err := openCache(defaultSettings(t).cfg, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if !exists("old.json") {
+		t.Error("old.json removed again within the trim interval")
+	}
+}
@@ -0,0 +1,246 @@
+package analizer
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// contractMarker marks an interface implemented by external consumers (plugin SPI, callbacks).
+// Like other directives it has no space after the slashes, so gofmt keeps it as is.
+const contractMarker = "//unused:contract"
+
+// contractMarkerAlias is the short marker first requested for contracts, accepted
+// as well. It is written without a space too, so prose starting with "contract" does not count.
+const contractMarkerAlias = "//contract"
+
+// contract is an interface or method declared as a public contract
+type contract struct {
+	name            string       // qualified name, pkg.Iface or pkg.Iface.Method
+	source          string       // how it was declared: marker or config
+	iface           *types.Named // interface type
+	implementations int          // number of implementing production types in the loaded packages
+	mocks           int          // number of implementing generated mocks
+}
+
+// hasContractMarker checks if the type declaration has a //unused:contract or //contract comment
+func hasContractMarker(gd *ast.GenDecl, tspec *ast.TypeSpec) bool {
+	groups := []*ast.CommentGroup{tspec.Doc, tspec.Comment}
+	if !gd.Lparen.IsValid() {
+		// Ungrouped declaration, the doc comment belongs to the GenDecl
+		groups = append(groups, gd.Doc)
+	}
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			text := strings.TrimRight(comment.Text, " \t")
+			if text == contractMarker || text == contractMarkerAlias {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RunContracts executes the contracts subcommand and exits
+func RunContracts() {
+	os.Exit(runContracts(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runContracts lists declared contracts with their implementation counts, returning the exit code
+func runContracts(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("contracts", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s contracts [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Lists interfaces and methods declared as public contracts")
+		fmt.Fprintf(stderr, "(by %s comment or contracts config) with their implementation counts.\n\n", contractMarker)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	pkgs, err := loadPackages(patterns, false, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	contracts := s.collectContracts(pkgs)
+	s.countImplementations(pkgs, contracts)
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTRACT\tSOURCE\tIMPLEMENTATIONS\tMOCKS")
+	for _, c := range contracts {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", c.name, c.source, c.implementations, c.mocks)
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// collectContracts finds interfaces and methods declared as contracts, sorted by name
+func (s *settings) collectContracts(pkgs []*packages.Package) []*contract {
+	var contracts []*contract
+	seen := make(map[string]bool)
+
+	add := func(name, source string, iface *types.Named) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		contracts = append(contracts, &contract{name: name, source: source, iface: iface})
+	}
+
+	for _, pkg := range pkgs {
+		pkgPath := pkg.PkgPath
+		for _, file := range pkg.Syntax {
+			if s.cfg.ShouldIgnore(s.relativePath(pkg.Fset.Position(file.Pos()).Filename)) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					tspec := spec.(*ast.TypeSpec)
+					obj := pkg.TypesInfo.Defs[tspec.Name]
+					if obj == nil {
+						continue
+					}
+					named, ok := obj.Type().(*types.Named)
+					if !ok {
+						continue
+					}
+					ifaceType, ok := named.Underlying().(*types.Interface)
+					if !ok {
+						continue
+					}
+
+					qualified := pkgPath + "." + tspec.Name.Name
+					switch {
+					case hasContractMarker(gd, tspec):
+						add(qualified, "marker", named)
+					case s.cfg.IsContract(pkgPath, tspec.Name.Name, ""):
+						add(qualified, "config", named)
+					default:
+						for i := 0; i < ifaceType.NumExplicitMethods(); i++ {
+							m := ifaceType.ExplicitMethod(i)
+							if s.cfg.IsContract(pkgPath, tspec.Name.Name, m.Name()) {
+								add(qualified+"."+m.Name(), "config", named)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].name < contracts[j].name
+	})
+	return contracts
+}
+
+// countImplementations counts concrete named types implementing each contract interface.
+// Generated mocks are counted separately, other types declared in ignored files
+// (test helpers) are not counted.
+func (s *settings) countImplementations(pkgs []*packages.Package, contracts []*contract) {
+	concrete, mockTypes := s.concreteTypes(pkgs)
+	for _, c := range contracts {
+		iface, ok := c.iface.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		c.implementations = len(implementing(concrete, iface))
+		c.mocks = len(implementing(mockTypes, iface))
+	}
+}
+
+// concreteTypes returns non-generic concrete named types declared in the packages,
+// separating generated mocks and skipping other types declared in ignored files
+func (s *settings) concreteTypes(pkgs []*packages.Package) (concrete, mockTypes []*types.Named) {
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+	mocks := newMockDetector(pkgs[0].Fset)
+	seen := make(map[types.Object]bool)
+
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() || seen[obj] {
+				continue
+			}
+			seen[obj] = true
+			named, ok := obj.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() > 0 {
+				continue
+			}
+			if mocks.isMock(named) {
+				mockTypes = append(mockTypes, named)
+				continue
+			}
+			if s.cfg.ShouldIgnore(s.relativePath(pkg.Fset.Position(obj.Pos()).Filename)) {
+				continue
+			}
+			concrete = append(concrete, named)
+		}
+	}
+	return concrete, mockTypes
+}
+
+// implementing returns types whose value or pointer implements the interface
+func implementing(named []*types.Named, iface *types.Interface) []*types.Named {
+	var result []*types.Named
+	for _, t := range named {
+		if types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// implementationNames returns the sorted names of the types implementing the interface,
+// qualified relative to the package declaring it. Test variants of a package declare
+// the same types again, so each name is returned once.
+func implementationNames(named []*types.Named, iface *types.Interface, declPkg *types.Package) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range implementing(named, iface) {
+		typeName := types.TypeString(t, types.RelativeTo(declPkg))
+		if !seen[typeName] {
+			seen[typeName] = true
+			names = append(names, typeName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,63 @@
+package analizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestAnalyzerContracts(t *testing.T) {
+	analyzer := NewAnalyzer(&config.Config{Contracts: []string{"contracts.Callback.OnDone"}})
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer, "contracts")
+}
+
+func TestCollectContracts(t *testing.T) {
+	s := &settings{cfg: &config.Config{Contracts: []string{"contracts.Callback.OnDone"}}}
+
+	testdata, err := filepath.Abs(analysistest.TestData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.LoadSyntax,
+		Dir:  filepath.Join(testdata, "src"),
+		Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}, "contracts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("errors loading packages")
+	}
+
+	contracts := s.collectContracts(pkgs)
+	s.countImplementations(pkgs, contracts)
+
+	type row struct {
+		name            string
+		source          string
+		implementations int
+		mocks           int
+	}
+	want := []row{
+		{"contracts.Callback.OnDone", "config", 0, 0},
+		{"contracts.Hook", "marker", 0, 0},
+		{"contracts.Plugin", "marker", 2, 1},
+		{"contracts.Sink", "marker", 0, 0},
+	}
+	if len(contracts) != len(want) {
+		t.Fatalf("collectContracts() returned %d contracts, want %d", len(contracts), len(want))
+	}
+	for i, c := range contracts {
+		got := row{c.name, c.source, c.implementations, c.mocks}
+		if got != want[i] {
+			t.Errorf("contract %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
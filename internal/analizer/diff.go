@@ -0,0 +1,354 @@
+package analizer
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+)
+
+// gitChanges are the lines of the working tree added or modified since a revision,
+// keyed by slash-separated path relative to the repository root
+type gitChanges struct {
+	lines     map[string]map[int]bool
+	untracked map[string]bool // new files, all lines changed
+}
+
+// contains reports whether the line of the file was changed
+func (c *gitChanges) contains(file string, line int) bool {
+	return c.untracked[file] || c.lines[file][line]
+}
+
+// newFindingsSince returns the findings introduced in the working tree since the
+// git revision: those declared on changed lines, and those missing from the
+// analysis of the revision because the change removed the last call site.
+// Only the latter need the revision to be analyzed, renamed files count as new.
+func (s *settings) newFindingsSince(rev string, findings []finding, patterns []string, tests bool, tags, mode string, module bool) ([]finding, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	root, err := gitRoot(cwd)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := changedSince(root, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var introduced, unchanged []finding
+	for _, f := range findings {
+		// Findings outside the repository, like other modules of a workspace, are not part of the change
+		file, ok := repoPath(root, f.Posn.Filename)
+		switch {
+		case !ok:
+		case changes.contains(file, f.Posn.Line):
+			introduced = append(introduced, f)
+		default:
+			unchanged = append(unchanged, f)
+		}
+	}
+	if len(unchanged) == 0 {
+		return introduced, nil
+	}
+
+	before, err := s.revisionFindings(root, cwd, rev, patterns, tests, tags, mode, module)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing revision %s: %w", rev, err)
+	}
+	for _, f := range unchanged {
+		file, _ := repoPath(root, f.Posn.Filename)
+		if !before[revisionFindingKey(file, f)] {
+			introduced = append(introduced, f)
+		}
+	}
+	return introduced, nil
+}
+
+// revisionFindings analyzes the revision extracted to a temporary directory
+// the way the working tree is analyzed, from the same directory of the tree
+// and with the configuration file of the revision
+func (s *settings) revisionFindings(root, cwd, rev string, patterns []string, tests bool, tags, mode string, module bool) (map[string]bool, error) {
+	dir, err := os.MkdirTemp("", "unused-interface-methods-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := extractRevision(root, rev, dir); err != nil {
+		return nil, err
+	}
+
+	remap := func(path string) string {
+		if rel, err := filepath.Rel(root, path); err == nil && filepath.IsLocal(rel) {
+			return filepath.Join(dir, rel)
+		}
+		return path
+	}
+	revPatterns := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		if filepath.IsAbs(pattern) {
+			pattern = remap(pattern)
+		}
+		revPatterns[i] = pattern
+	}
+	revSettings := &settings{}
+	if s.basePath != "" {
+		revSettings.basePath = remap(s.basePath)
+	}
+	if revSettings.cfg, err = config.LoadConfigFrom(remap(cwd)); err != nil {
+		return nil, err
+	}
+	if mode != "" {
+		if err := revSettings.cfg.SetMode(mode); err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs, err := loadPackagesIn(remap(cwd), revPatterns, tests, tags)
+	if err != nil {
+		return nil, err
+	}
+	if module {
+		revSettings.moduleScope = newModuleScope(pkgs)
+	}
+	results, err := revSettings.analyzePackages(pkgs)
+	if err != nil {
+		return nil, err
+	}
+	// Packages failing only in the revision leave their findings counted as new
+	var findings []finding
+	if module {
+		findings, _ = revSettings.moduleFindings(io.Discard, results)
+	} else {
+		findings, _ = packageFindings(io.Discard, results)
+	}
+
+	keys := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		if file, ok := repoPath(dir, f.Posn.Filename); ok {
+			keys[revisionFindingKey(file, f)] = true
+		}
+	}
+	return keys, nil
+}
+
+// revisionFindingKey identifies a finding across revisions, independently of
+// line shifts, by the file relative to the repository root
+func revisionFindingKey(file string, f finding) string {
+	return file + "\x00" + string(f.Severity) + "\x00" + f.Message
+}
+
+// repoPath returns the slash-separated path of the file relative to the
+// repository root, or false if the file is outside the repository
+func repoPath(root, filename string) (string, bool) {
+	rel, err := filepath.Rel(root, filename)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// gitRoot returns the top-level directory of the git repository containing dir.
+// It is derived from dir rather than resolved by git, so it has the same form
+// as the file names of loaded packages when dir is reached through a symlink.
+func gitRoot(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-cdup")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, strings.TrimSpace(string(out))), nil
+}
+
+// changedSince returns the lines of the working tree changed since the revision,
+// including staged and unstaged changes and untracked files
+func changedSince(root, rev string) (*gitChanges, error) {
+	out, err := git(root, "diff", "--no-color", "--no-ext-diff", "--no-renames", "-U0", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	changes := &gitChanges{lines: parseDiff(out), untracked: make(map[string]bool)}
+
+	out, err = git(root, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file != "" {
+			changes.untracked[file] = true
+		}
+	}
+	return changes, nil
+}
+
+// parseDiff returns the added and modified lines of the new files in a unified diff
+// without context lines, by slash-separated path relative to the repository root
+func parseDiff(diff []byte) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	var file string
+	header := false // between "diff --git" and the first hunk, where "+++" names the new file
+	for _, line := range strings.Split(string(diff), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			header, file = true, ""
+		case header && strings.HasPrefix(line, "+++ "):
+			// Deleted files are named /dev/null, git appends a tab to names with spaces
+			// and quotes names with control characters, quotes or backslashes
+			name := strings.TrimSuffix(line[len("+++ "):], "\t")
+			if unquoted, err := strconv.Unquote(name); err == nil && strings.HasPrefix(name, `"`) {
+				name = unquoted
+			}
+			if strings.HasPrefix(name, "b/") {
+				file = name[len("b/"):]
+			}
+		case strings.HasPrefix(line, "@@ "):
+			header = false
+			start, count, ok := parseHunkHeader(line)
+			if !ok || file == "" || count == 0 {
+				continue
+			}
+			if lines[file] == nil {
+				lines[file] = make(map[int]bool)
+			}
+			for n := start; n < start+count; n++ {
+				lines[file][n] = true
+			}
+		}
+	}
+	return lines
+}
+
+// parseHunkHeader returns the first line and the number of lines of the new
+// file in a hunk header like "@@ -10,2 +12,3 @@ func f() {"
+func parseHunkHeader(line string) (start, count int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	startText, countText, hasCount := strings.Cut(fields[2][1:], ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// extractRevision writes the files of the revision to dir, leaving the
+// repository and its worktrees untouched. The archive is extracted while
+// git writes it, so the revision is never held in memory.
+func extractRevision(root, rev, dir string) error {
+	cmd := gitCommand(root, "archive", "--format=tar", rev)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return gitError("archive", &stderr, err)
+	}
+	err = extractTar(out, dir)
+	if err == nil {
+		// Padding after the end of the archive
+		_, err = io.Copy(io.Discard, out)
+	} else {
+		// git would block writing the rest of the archive
+		cmd.Process.Kill()
+	}
+	if waitErr := cmd.Wait(); waitErr != nil && err == nil {
+		err = gitError("archive", &stderr, waitErr)
+	}
+	return err
+}
+
+// extractTar writes the files of the tar archive to dir
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("git archive: invalid path %q", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = writeArchiveFile(target, tr, hdr.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+				err = os.Symlink(hdr.Linkname, target)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeArchiveFile writes a regular file of the archive, creating its directory
+func writeArchiveFile(target string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// git runs git in dir and returns its standard output, with the
+// standard error of git as the error message if it fails
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := gitCommand(dir, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, gitError(args[0], &stderr, err)
+	}
+	return out, nil
+}
+
+// gitCommand returns the command running git in dir. File names in its output
+// are quoted only for control characters, quotes and backslashes, not for
+// non-ASCII characters, whatever core.quotePath is set to.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// gitError returns the error of the git subcommand, with its standard error as the message
+func gitError(subcommand string, stderr *bytes.Buffer, err error) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("git %s: %s", subcommand, msg)
+	}
+	return fmt.Errorf("git %s: %w", subcommand, err)
+}
@@ -0,0 +1,108 @@
+package analizer
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDiff(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/store/store.go b/store/store.go",
+		"index 1111111..2222222 100644",
+		"--- a/store/store.go",
+		"+++ b/store/store.go",
+		"@@ -5,0 +6,2 @@ type Store interface {",
+		"+\tDelete()",
+		"++++ not a file name",
+		"@@ -9 +11 @@ func f() {",
+		"-\told",
+		"+\tnew",
+		"@@ -20,3 +22,0 @@",
+		"-\tremoved",
+		"diff --git a/old.go b/old.go",
+		"deleted file mode 100644",
+		"--- a/old.go",
+		"+++ /dev/null",
+		"@@ -1,3 +0,0 @@",
+		"-package old",
+		"diff --git a/new.go b/new.go",
+		"new file mode 100644",
+		"--- /dev/null",
+		"+++ b/new.go",
+		"@@ -0,0 +1,2 @@",
+		"+package main",
+		"+",
+		`diff --git "a/tab\there.go" "b/tab\there.go"`,
+		`--- "a/tab\there.go"`,
+		`+++ "b/tab\there.go"`,
+		"@@ -3 +3 @@",
+		"+\tx := 1",
+	}, "\n")
+	want := map[string]map[int]bool{
+		"store/store.go": {6: true, 7: true, 11: true},
+		"new.go":         {1: true, 2: true},
+		"tab\there.go":   {3: true},
+	}
+	if got := parseDiff([]byte(diff)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDiff() = %v, want %v", got, want)
+	}
+}
+
+func TestNewFromRev(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	isolateGoEnv(t)
+	root := writeModule(t, map[string]string{
+		"go.mod":                            "module example.com/d\n\ngo 1.24\n",
+		"app/.unused-interface-methods.yml": "ignore-interfaces:\n  - example.com/d/store.Store.Legacy\n",
+		"store/store.go":                    "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n\tLegacy()\n}\n",
+		"app/app.go":                        "package app\n\nimport \"example.com/d/store\"\n\nfunc Use(s store.Store) string {\n\ts.Put()\n\treturn s.Get()\n}\n",
+	})
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	run("init", "-q")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	// Delete is declared in the change, the change removes the last call of Put
+	// and the configuration ignoring Legacy
+	if err := os.Remove(filepath.Join(root, "app", ".unused-interface-methods.yml")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, root, map[string]string{
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n\tLegacy()\n\tDelete()\n}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/d/store\"\n\nfunc Use(s store.Store) string {\n\treturn s.Get()\n}\n",
+	})
+	t.Chdir(filepath.Join(root, "app"))
+
+	var stdout, stderr bytes.Buffer
+	code := runDriver([]string{"-new-from-rev=HEAD", "-module", "-cache=false", "-test=false", "../..."}, &stdout, &stderr)
+	if code != exitFindings {
+		t.Errorf("runDriver() = %d, want %d; stderr: %s", code, exitFindings, stderr.String())
+	}
+	storeFile := filepath.Join(root, "store", "store.go")
+	want := storeFile + ":5:2: method \"Put\" of interface \"Store\" is declared but not used\n" +
+		storeFile + ":6:2: method \"Legacy\" of interface \"Store\" is declared but not used\n" +
+		storeFile + ":7:2: method \"Delete\" of interface \"Store\" is declared but not used\n"
+	if got := stderr.String(); got != want {
+		t.Errorf("runDriver() output:\n%s\nwant:\n%s", got, want)
+	}
+
+	stderr.Reset()
+	if code := runDriver([]string{"-new-from-rev=no-such-rev", "-module", "-cache=false", "-test=false", "../..."}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("runDriver() with an unknown revision = %d, want %d", code, exitFailure)
+	}
+}
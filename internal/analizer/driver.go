@@ -0,0 +1,501 @@
+package analizer
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Exit codes of the standalone command
+const (
+	exitOK       = 0 // no error-level findings
+	exitFailure  = 1 // packages could not be loaded or analyzed
+	exitFindings = 3 // at least one error-level finding, same as go vet
+)
+
+// Run executes the analyzer as a standalone command and exits.
+// The exit code reflects only error-level findings, so warnings and
+// infos configured via severity rules never fail the run.
+func Run() {
+	os.Exit(runDriver(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runDriver parses flags, loads packages and reports findings, returning the exit code
+func runDriver(args []string, stdout, stderr io.Writer) int {
+	if isVetInvocation(args) {
+		runVet(args, stdout, stderr)
+	}
+
+	fs := flag.NewFlagSet(a.Name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	jsonOutput := fs.Bool("json", false, "emit JSON output, same as -format=json")
+	format := fs.String("format", "text", "output format: "+strings.Join(outputFormats, ", "))
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	mode := fs.String("mode", "", "report only interfaces of this kind: all, exported, unexported, internal")
+	module := fs.Bool("module", false, "report only methods unused across all loaded packages")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	workspace := fs.Bool("workspace", false, "analyze all modules of the go.work workspace together, implies -module")
+	watch := fs.Bool("watch", false, "keep running and print findings added (+) or removed (-) by file changes")
+	useCache := fs.Bool("cache", true, "reuse results of unchanged packages from the cache in the user cache directory")
+	newFromRev := fs.String("new-from-rev", "", "report only findings introduced since the git revision: declared on changed lines or left unused by removed call sites")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "%s: %s\n\n", a.Name, a.Doc)
+		fmt.Fprintf(stderr, "Usage: %s [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	if *mode != "" {
+		if err := s.cfg.SetMode(*mode); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+	}
+
+	patterns := fs.Args()
+	if *workspace {
+		if len(patterns) > 0 {
+			fmt.Fprintf(stderr, "%s: -workspace does not take package patterns\n", a.Name)
+			return exitFailure
+		}
+		gowork, dirs, err := workspaceModules("")
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+		s.basePath = filepath.Dir(gowork)
+		for _, dir := range dirs {
+			patterns = append(patterns, filepath.Join(dir, "..."))
+		}
+		*module = true
+	}
+	if len(patterns) == 0 {
+		fs.Usage()
+		return exitFailure
+	}
+	if *jsonOutput {
+		*format = "json"
+	}
+	if !validFormat(*format) {
+		fmt.Fprintf(stderr, "%s: invalid format %q, want one of %s\n", a.Name, *format, strings.Join(outputFormats, ", "))
+		return exitFailure
+	}
+	if *watch && *format != "text" {
+		fmt.Fprintf(stderr, "%s: -watch does not support -format=%s\n", a.Name, *format)
+		return exitFailure
+	}
+	if *watch && *newFromRev != "" {
+		fmt.Fprintf(stderr, "%s: -watch does not support -new-from-rev\n", a.Name)
+		return exitFailure
+	}
+
+	if *module {
+		// Usage is tracked in every package importing the declaring one
+		s.moduleScope = make(map[string]bool)
+	}
+
+	var cache *analysisCache
+	if *useCache {
+		var err error
+		flags := fmt.Sprintf("test=%t tags=%s module=%t basePath=%s", *tests, *tags, *module, s.basePath)
+		if cache, err = openCache(s.cfg, flags); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Cache disabled: %v\n", err)
+		}
+	}
+
+	results, err := s.loadAndAnalyze("", patterns, *tests, *tags, cache)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	var findings []finding
+	var code int
+	if *module {
+		findings, code = s.moduleFindings(stderr, results)
+	} else {
+		findings, code = packageFindings(stderr, results)
+	}
+	if *newFromRev != "" {
+		if findings, err = s.newFindingsSince(*newFromRev, findings, patterns, *tests, *tags, *mode, *module); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+	}
+	switch *format {
+	case "json":
+		if err := printFindingsJSON(stdout, findings); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+		return code // like go vet -json, findings do not fail the run
+	case "text":
+	default:
+		if err := printFindingsFormat(stdout, *format, findings, results); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+		return findingsExitCode(findings, code)
+	}
+	code = printFindings(stderr, findings, code)
+	if *workspace {
+		printModuleSummaries(stderr, summarizeModules(results, findings))
+	}
+
+	if *watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := newWatcher(s, "", stderr, results, *tests, *tags, *module, cache).run(ctx); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitFailure
+		}
+		return exitOK
+	}
+	return code
+}
+
+// loadPackages loads packages matching the patterns with syntax and type information.
+// Patterns are anything go list accepts: relative and absolute directories,
+// import paths, "std" and "..." wildcards.
+func loadPackages(patterns []string, tests bool, tags string) ([]*packages.Package, error) {
+	return loadPackagesIn("", patterns, tests, tags)
+}
+
+// loadPackagesIn loads packages like loadPackages, resolving the patterns in dir,
+// or in the current directory when dir is empty
+func loadPackagesIn(dir string, patterns []string, tests bool, tags string) ([]*packages.Package, error) {
+	return loadPackagesMode(packages.LoadSyntax|packages.NeedForTest|packages.NeedModule, dir, patterns, tests, tags)
+}
+
+// listPackagesIn lists packages like loadPackagesIn with their files and all their
+// dependencies, without parsing or type-checking them
+func listPackagesIn(dir string, patterns []string, tests bool, tags string) ([]*packages.Package, error) {
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedEmbedFiles | packages.NeedImports |
+		packages.NeedDeps | packages.NeedForTest | packages.NeedModule
+	return loadPackagesMode(mode, dir, patterns, tests, tags)
+}
+
+// loadPackagesMode loads the packages matching the patterns in dir with the load mode
+func loadPackagesMode(mode packages.LoadMode, dir string, patterns []string, tests bool, tags string) ([]*packages.Package, error) {
+	var buildFlags []string
+	if tags != "" {
+		buildFlags = []string{"-tags=" + tags}
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:       mode,
+		Dir:        dir,
+		Tests:      tests,
+		BuildFlags: buildFlags,
+	}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("%s matched no packages", strings.Join(patterns, " "))
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("%d errors loading packages", n)
+	}
+	return dropTestedVariants(pkgs), nil
+}
+
+// dropTestedVariants drops packages that also have an in-package test variant.
+// The test variant contains all files of the package plus its _test.go files,
+// so analyzing both would report methods used only from tests twice.
+func dropTestedVariants(pkgs []*packages.Package) []*packages.Package {
+	tested := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.ForTest != "" && pkg.ForTest == pkg.PkgPath {
+			tested[pkg.PkgPath] = true
+		}
+	}
+
+	result := pkgs[:0:0]
+	for _, pkg := range pkgs {
+		if pkg.ForTest == "" && tested[pkg.PkgPath] {
+			continue
+		}
+		result = append(result, pkg)
+	}
+	return result
+}
+
+// packageResult is the outcome of analyzing one package, computed or read from the cache
+type packageResult struct {
+	pkg   *packages.Package
+	usage *packageUsage // nil if the analysis failed
+	err   error
+}
+
+// loadAndAnalyze loads and analyzes the packages matching the patterns in dir like
+// loadPackagesIn, adding them to the module scope if it is set. With a cache, which
+// may be nil, the packages are listed first and only those missing from the cache
+// are loaded with syntax and types and analyzed.
+func (s *settings) loadAndAnalyze(dir string, patterns []string, tests bool, tags string, cache *analysisCache) ([]*packageResult, error) {
+	if cache == nil {
+		pkgs, err := loadPackagesIn(dir, patterns, tests, tags)
+		if err != nil {
+			return nil, err
+		}
+		s.addModuleScope(pkgs)
+		return s.analyzePackages(pkgs)
+	}
+
+	pkgs, err := listPackagesIn(dir, patterns, tests, tags)
+	if err != nil {
+		return nil, err
+	}
+	s.addModuleScope(pkgs)
+	keys := cache.keys(pkgs, s.moduleScope)
+
+	results := make([]*packageResult, len(pkgs))
+	indexes := make(map[string]int) // of packages missing from the cache by ID
+	for i, pkg := range pkgs {
+		if usage, ok := cache.get(keys[pkg]); ok {
+			results[i] = &packageResult{pkg: pkg, usage: usage}
+			continue
+		}
+		indexes[pkg.ID] = i
+	}
+	if verbose {
+		cache.printStats(os.Stderr)
+	}
+	if len(indexes) == 0 {
+		return results, nil
+	}
+
+	loaded, err := loadPackagesIn(dir, missingPatterns(pkgs, indexes, patterns), tests, tags)
+	if err != nil {
+		return nil, err
+	}
+	var missing []*packages.Package
+	for _, pkg := range loaded {
+		if _, ok := indexes[pkg.ID]; ok {
+			missing = append(missing, pkg)
+		}
+	}
+	if len(missing) != len(indexes) {
+		return nil, fmt.Errorf("%d packages changed while loading", len(indexes)-len(missing))
+	}
+	analyzed, err := s.analyzePackages(missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range analyzed {
+		i := indexes[result.pkg.ID]
+		if result.err == nil {
+			cache.put(keys[pkgs[i]], result.usage)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// missingPatterns returns the import paths loading the packages with the IDs in
+// missing, or the original patterns for packages named by their files
+func missingPatterns(pkgs []*packages.Package, missing map[string]int, patterns []string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, pkg := range pkgs {
+		if _, ok := missing[pkg.ID]; !ok {
+			continue
+		}
+		if pkg.PkgPath == "command-line-arguments" {
+			return patterns
+		}
+		if path := basePkgPath(pkg); !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// addModuleScope adds the import paths of the packages to the module scope, if set
+func (s *settings) addModuleScope(pkgs []*packages.Package) {
+	if s.moduleScope == nil {
+		return
+	}
+	for _, pkg := range pkgs {
+		s.moduleScope[pkg.PkgPath] = true
+	}
+}
+
+// analyzePackages analyzes the packages with the settings and returns
+// their results in the order of the packages
+func (s *settings) analyzePackages(pkgs []*packages.Package) ([]*packageResult, error) {
+	graph, err := checker.Analyze([]*analysis.Analyzer{newAnalyzer(s)}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[*packages.Package]int, len(pkgs))
+	for i, pkg := range pkgs {
+		indexes[pkg] = i
+	}
+	results := make([]*packageResult, len(pkgs))
+	for _, act := range graph.Roots {
+		result := &packageResult{pkg: act.Package, err: act.Err}
+		if act.Err == nil {
+			usage := act.Result.(*packageUsage)
+			for _, diag := range act.Diagnostics {
+				usage.Findings = append(usage.Findings, newFinding(act.Package, diag))
+			}
+			result.usage = usage
+		}
+		results[indexes[act.Package]] = result
+	}
+	return results, nil
+}
+
+// finding is a diagnostic resolved to its position
+type finding struct {
+	PkgID    string          `json:"pkg"` // package reporting the finding
+	Posn     token.Position  `json:"posn"`
+	End      token.Position  `json:"end"` // end of the method name, if known
+	Severity config.Severity `json:"severity"`
+	Message  string          `json:"message"`
+}
+
+// newFinding resolves a diagnostic reported for the package
+func newFinding(pkg *packages.Package, diag analysis.Diagnostic) finding {
+	f := finding{
+		PkgID:    pkg.ID,
+		Posn:     pkg.Fset.Position(diag.Pos),
+		Severity: severityOf(diag),
+		Message:  diag.Message,
+	}
+	if diag.End.IsValid() {
+		f.End = pkg.Fset.Position(diag.End)
+	}
+	return f
+}
+
+// reportResultError prints the error of a package whose analysis failed
+func reportResultError(w io.Writer, result *packageResult) {
+	fmt.Fprintf(w, "%s: %v\n", a.Name, result.err)
+}
+
+// packageFindings returns findings of per-package analysis with the exit code of failed packages
+func packageFindings(w io.Writer, results []*packageResult) ([]finding, int) {
+	code := exitOK
+	seen := make(map[finding]bool) // test variants share files
+	var findings []finding
+	for _, result := range results {
+		if result.err != nil {
+			reportResultError(w, result)
+			code = exitFailure
+			continue
+		}
+		for _, f := range result.usage.Findings {
+			key := f
+			key.PkgID = ""
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, f)
+		}
+	}
+	return findings, code
+}
+
+// printFindings prints findings sorted by position and returns the exit code,
+// which is raised to exitFindings by error-level findings
+func printFindings(w io.Writer, findings []finding, code int) int {
+	sortFindings(findings)
+	for _, f := range findings {
+		fmt.Fprintln(w, formatFinding(f))
+	}
+	return findingsExitCode(findings, code)
+}
+
+// findingsExitCode raises the exit code to exitFindings if any finding is error-level
+func findingsExitCode(findings []finding, code int) int {
+	for _, f := range findings {
+		if f.Severity == config.SeverityError && code == exitOK {
+			code = exitFindings
+		}
+	}
+	return code
+}
+
+// formatFinding formats a finding in go vet format, prefixing the message
+// with the severity below error level
+func formatFinding(f finding) string {
+	if f.Severity == config.SeverityError {
+		return fmt.Sprintf("%s: %s", f.Posn, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Posn, f.Severity, f.Message)
+}
+
+// printFindingsJSON prints findings in the go vet -json format,
+// grouped by package and analyzer
+func printFindingsJSON(w io.Writer, findings []finding) error {
+	type jsonDiagnostic struct {
+		Category string `json:"category,omitempty"`
+		Posn     string `json:"posn"`
+		Message  string `json:"message"`
+	}
+
+	sortFindings(findings)
+	tree := make(map[string]map[string][]jsonDiagnostic)
+	for _, f := range findings {
+		if tree[f.PkgID] == nil {
+			tree[f.PkgID] = make(map[string][]jsonDiagnostic)
+		}
+		tree[f.PkgID][a.Name] = append(tree[f.PkgID][a.Name], jsonDiagnostic{
+			Category: string(f.Severity),
+			Posn:     f.Posn.String(),
+			Message:  f.Message,
+		})
+	}
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// sortFindings sorts findings by file, line and column
+func sortFindings(findings []finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		pi, pj := findings[i].Posn, findings[j].Posn
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+}
+
+// severityOf returns the severity stored in the diagnostic category
+func severityOf(diag analysis.Diagnostic) config.Severity {
+	if diag.Category == "" {
+		return config.SeverityError
+	}
+	return config.Severity(diag.Category)
+}
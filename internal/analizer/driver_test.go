@@ -0,0 +1,109 @@
+package analizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadTestdata loads testdata packages with their tests the way the driver does
+func loadTestdata(t *testing.T, patterns ...string) []*packages.Package {
+	t.Helper()
+
+	testdata, err := filepath.Abs(analysistest.TestData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedForTest,
+		Dir:   filepath.Join(testdata, "src"),
+		Tests: true,
+		Env:   append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}, patterns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("errors loading packages")
+	}
+	return dropTestedVariants(pkgs)
+}
+
+// defaultSettings returns the settings of the default configuration
+func defaultSettings(tb testing.TB) *settings {
+	tb.Helper()
+	cfg, err := config.ParseSettings(nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return &settings{cfg: cfg}
+}
+
+func TestDriverTestOnlyUsage(t *testing.T) {
+	pkgs := loadTestdata(t, "testusage")
+	for _, pkg := range pkgs {
+		if pkg.ID == "testusage" {
+			t.Errorf("package %s has a test variant and should be dropped", pkg.ID)
+		}
+	}
+
+	results, err := defaultSettings(t).analyzePackages(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	findings, code := packageFindings(&buf, results)
+	code = printFindings(&buf, findings, code)
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		got = append(got, filepath.Base(line))
+	}
+	want := []string{
+		`testusage.go:8:2: warning: method "Put" of interface "Store" is only used from tests`,
+		`testusage.go:9:2: method "Delete" of interface "Store" is declared but not used`,
+		`testusage.go:18:2: warning: method "Run" of interface "Runner" is only used from tests`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("packageFindings() output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if code != exitFindings {
+		t.Errorf("printFindings() = %d, want %d", code, exitFindings)
+	}
+}
+
+func TestDriverModule(t *testing.T) {
+	pkgs := loadTestdata(t, "modulewide/...")
+	s := defaultSettings(t)
+	s.moduleScope = newModuleScope(pkgs)
+	results, err := s.analyzePackages(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	findings, code := s.moduleFindings(&buf, results)
+	code = printFindings(&buf, findings, code)
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		got = append(got, filepath.Base(line))
+	}
+	want := []string{
+		`store.go:5:2: warning: method "Put" of interface "Store" is only used from tests`,
+		// Known limitation: the concrete call in modulewide/purge is not credited
+		// because that package does not import modulewide/store
+		`store.go:6:2: method "Delete" of interface "Store" is declared but not used`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("moduleFindings() output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if code != exitFindings {
+		t.Errorf("printFindings() = %d, want %d", code, exitFindings)
+	}
+}
@@ -0,0 +1,89 @@
+package analizer
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+)
+
+// RunExplain executes the explain subcommand and exits
+func RunExplain() {
+	os.Exit(runExplain(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runExplain prints the sites using an interface method across the loaded packages
+// and the rule that matched each of them, returning the exit code
+func runExplain(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s explain [-flag] pkg.Iface.Method [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Prints every site using the interface method in the loaded packages (./... by default)")
+		fmt.Fprintln(stderr, "and the rule that matched it, to audit why the method is not reported.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitFailure
+	}
+
+	name := fs.Arg(0)
+	if strings.Count(name, ".") < 2 {
+		fmt.Fprintf(stderr, "%s: %q is not a qualified method name, want pkg.Iface.Method\n", a.Name, name)
+		return exitFailure
+	}
+	patterns := fs.Args()[1:]
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	_, results, err := s.analyzeModule(stderr, patterns, *tests, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	idx := newUsageIndex(results)
+	method, ok := idx.declared[name]
+	if !ok {
+		fmt.Fprintf(stderr, "%s: method %s is not declared in the loaded packages or is ignored by the configuration\n", a.Name, name)
+		return exitFailure
+	}
+	sites := idx.sites[name]
+
+	fmt.Fprintf(stdout, "%s declared at %s\n", name, s.relativePosition(method.Posn))
+	if method.Contract {
+		fmt.Fprintln(stdout, "public contract, never reported")
+	}
+	if len(sites) == 0 {
+		fmt.Fprintln(stdout, "not used in the loaded packages")
+		return exitOK
+	}
+	fmt.Fprintf(stdout, "used at %d %s:\n", len(sites), plural(len(sites), "site", "sites"))
+	for _, site := range sites {
+		rule := string(site.Rule)
+		if site.Test {
+			rule += " (test)"
+		}
+		fmt.Fprintf(stdout, "  %s: %s\n", s.relativePosition(site.Posn), rule)
+	}
+	return exitOK
+}
+
+// relativePosition formats the position with the file name relative to its module root
+func (s *settings) relativePosition(posn token.Position) string {
+	return fmt.Sprintf("%s:%d:%d", s.relativePath(posn.Filename), posn.Line, posn.Column)
+}
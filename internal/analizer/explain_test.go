@@ -0,0 +1,45 @@
+package analizer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	chdirModule(t, map[string]string{
+		"go.mod":         "module example.com/e\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n}\n\ntype Memory struct{}\n\nfunc (Memory) Get() string { return \"\" }\nfunc (Memory) Put()          {}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/e/store\"\n\ntype wrapper struct{ store.Store }\n\nfunc Use(s store.Store, w wrapper, m store.Memory) string {\n\treturn s.Get() + w.Get() + m.Get()\n}\n\ntype cached struct{ store.Memory }\n\nvar getCached = cached.Get\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := runExplain([]string{"-test=false", "example.com/e/store.Store.Get"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runExplain() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	appGo := "app/app.go"
+	want := strings.Join([]string{
+		"example.com/e/store.Store.Get declared at store/store.go:4:2",
+		"used at 4 sites:",
+		"  " + appGo + ":8:9: direct selection",
+		"  " + appGo + ":8:19: embedding",
+		"  " + appGo + ":8:29: concrete type",
+		"  " + appGo + ":13:17: embedding",
+	}, "\n")
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Errorf("runExplain() output:\n%s\nwant:\n%s", got, want)
+	}
+
+	stdout.Reset()
+	if code := runExplain([]string{"-test=false", "example.com/e/store.Store.Put"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runExplain() = %d, want %d", code, exitOK)
+	}
+	if got := stdout.String(); !strings.HasSuffix(got, "not used in the loaded packages\n") {
+		t.Errorf("runExplain() for an unused method output:\n%s", got)
+	}
+
+	stderr.Reset()
+	if code := runExplain([]string{"-test=false", "example.com/e/store.Store.Drop"}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("runExplain() for an undeclared method = %d, want %d", code, exitFailure)
+	}
+}
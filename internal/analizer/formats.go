@@ -0,0 +1,276 @@
+package analizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+)
+
+// outputFormats are the values of the -format flag
+var outputFormats = []string{"text", "json", "checkstyle", "junit", "github", "codeclimate"}
+
+// validFormat reports whether the format is one of outputFormats
+func validFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// printFindingsFormat prints findings in one of the CI report formats
+func printFindingsFormat(w io.Writer, format string, findings []finding, results []*packageResult) error {
+	sortFindings(findings)
+	switch format {
+	case "checkstyle":
+		return printCheckstyle(w, findings)
+	case "junit":
+		return printJUnit(w, findings, declaredByPosition(results))
+	case "github":
+		return printGitHub(w, findings)
+	case "codeclimate":
+		return printCodeClimate(w, findings)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// declaredByPosition indexes the methods declared in the results by position,
+// which is the position of the findings reporting them
+func declaredByPosition(results []*packageResult) map[token.Position]declaredMethod {
+	declared := make(map[token.Position]declaredMethod)
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		for _, m := range result.usage.Declared {
+			declared[m.Posn] = m
+		}
+	}
+	return declared
+}
+
+// reportPath returns the file name relative to the current directory, where CI
+// jobs run from the repository root, or the file name unchanged outside of it
+func reportPath(filename string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, filename); err == nil && filepath.IsLocal(rel) {
+			filename = rel
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
+// printCheckstyle prints findings as a Checkstyle XML report, grouped by file
+func printCheckstyle(w io.Writer, findings []finding) error {
+	type checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+	type checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	type checkstyle struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+
+	report := checkstyle{Version: "5.0"}
+	for _, f := range findings {
+		name := reportPath(f.Posn.Filename)
+		if n := len(report.Files); n == 0 || report.Files[n-1].Name != name {
+			report.Files = append(report.Files, checkstyleFile{Name: name})
+		}
+		file := &report.Files[len(report.Files)-1]
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     f.Posn.Line,
+			Column:   f.Posn.Column,
+			Severity: string(f.Severity),
+			Message:  f.Message,
+			Source:   a.Name,
+		})
+	}
+	return writeXML(w, report)
+}
+
+// printJUnit prints findings as a JUnit XML report with a test suite per package
+// and a failed test case per method, named after its interface
+func printJUnit(w io.Writer, findings []finding, declared map[token.Position]declaredMethod) error {
+	type junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type junitTestCase struct {
+		Name      string       `xml:"name,attr"`
+		ClassName string       `xml:"classname,attr"`
+		Failure   junitFailure `xml:"failure"`
+	}
+	type junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+	type junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	suites := make(map[string]*junitTestSuite)
+	for _, f := range findings {
+		pkg, name, className := f.PkgID, f.Message, f.PkgID
+		if m, ok := declared[f.Posn]; ok {
+			pkg, name, className = m.PkgPath, m.Iface+"."+m.Method, m.PkgPath+"."+m.Iface
+		}
+		suite := suites[pkg]
+		if suite == nil {
+			suite = &junitTestSuite{Name: pkg}
+			suites[pkg] = suite
+		}
+		suite.Tests++
+		suite.Failures++
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      name,
+			ClassName: className,
+			Failure: junitFailure{
+				Message: f.Message,
+				Type:    string(f.Severity),
+				Text:    fmt.Sprintf("%s:%d:%d: %s", reportPath(f.Posn.Filename), f.Posn.Line, f.Posn.Column, f.Message),
+			},
+		})
+	}
+
+	report := junitTestSuites{Name: a.Name, Tests: len(findings), Failures: len(findings)}
+	for _, suite := range suites {
+		sort.SliceStable(suite.Cases, func(i, j int) bool {
+			return suite.Cases[i].Name < suite.Cases[j].Name
+		})
+		report.Suites = append(report.Suites, *suite)
+	}
+	sort.Slice(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+	return writeXML(w, report)
+}
+
+// githubCommands are the GitHub Actions workflow commands annotating findings of each severity
+var githubCommands = map[config.Severity]string{
+	config.SeverityError:   "error",
+	config.SeverityWarning: "warning",
+	config.SeverityInfo:    "notice",
+}
+
+// printGitHub prints findings as GitHub Actions workflow commands,
+// which the runner turns into annotations of the pull request
+func printGitHub(w io.Writer, findings []finding) error {
+	for _, f := range findings {
+		command, ok := githubCommands[f.Severity]
+		if !ok {
+			command = "warning"
+		}
+		_, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,title=%s::%s\n", command,
+			escapeGitHubProperty(reportPath(f.Posn.Filename)), f.Posn.Line, f.Posn.Column,
+			escapeGitHubProperty(a.Name), escapeGitHubData(f.Message))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGitHubData escapes the message of a workflow command
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// codeClimateSeverities are the Code Quality severities of findings of each severity
+var codeClimateSeverities = map[config.Severity]string{
+	config.SeverityError:   "major",
+	config.SeverityWarning: "minor",
+	config.SeverityInfo:    "info",
+}
+
+// printCodeClimate prints findings as a GitLab Code Quality report, a JSON array of
+// Code Climate issues. The fingerprint does not depend on the line, so GitLab keeps
+// tracking a finding whose method moves.
+func printCodeClimate(w io.Writer, findings []finding) error {
+	type codeClimateLines struct {
+		Begin int `json:"begin"`
+	}
+	type codeClimateLocation struct {
+		Path  string           `json:"path"`
+		Lines codeClimateLines `json:"lines"`
+	}
+	type codeClimateIssue struct {
+		Type        string              `json:"type"`
+		CheckName   string              `json:"check_name"`
+		Description string              `json:"description"`
+		Categories  []string            `json:"categories"`
+		Severity    string              `json:"severity"`
+		Fingerprint string              `json:"fingerprint"`
+		Location    codeClimateLocation `json:"location"`
+	}
+
+	issues := make([]codeClimateIssue, 0, len(findings))
+	for _, f := range findings {
+		path := reportPath(f.Posn.Filename)
+		severity, ok := codeClimateSeverities[f.Severity]
+		if !ok {
+			severity = "minor"
+		}
+		sum := sha256.Sum256([]byte(path + "\x00" + f.Message))
+		issues = append(issues, codeClimateIssue{
+			Type:        "issue",
+			CheckName:   a.Name,
+			Description: f.Message,
+			Categories:  []string{"Clarity"},
+			Severity:    severity,
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Location:    codeClimateLocation{Path: path, Lines: codeClimateLines{Begin: f.Posn.Line}},
+		})
+	}
+	data, err := json.MarshalIndent(issues, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// writeXML writes the indented XML document with its header
+func writeXML(w io.Writer, v any) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "\t")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
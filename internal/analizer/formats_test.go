@@ -0,0 +1,169 @@
+package analizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFormatModule writes a module with unused methods in two packages
+func writeFormatModule(t *testing.T) string {
+	t.Helper()
+	return writeModule(t, map[string]string{
+		"go.mod":         "module example.com/f\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n\tDelete()\n}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/f/store\"\n\ntype cache interface {\n\tEvict()\n}\n\nfunc Use(s store.Store) string { return s.Get() }\n",
+	})
+}
+
+func TestOutputFormats(t *testing.T) {
+	isolateGoEnv(t)
+	t.Chdir(writeFormatModule(t))
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"checkstyle", `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+	<file name="app/app.go">
+		<error line="6" column="2" severity="error" message="method &#34;Evict&#34; of interface &#34;cache&#34; is declared but not used" source="unused_interface_methods"></error>
+	</file>
+	<file name="store/store.go">
+		<error line="5" column="2" severity="error" message="method &#34;Put&#34; of interface &#34;Store&#34; is declared but not used" source="unused_interface_methods"></error>
+		<error line="6" column="2" severity="error" message="method &#34;Delete&#34; of interface &#34;Store&#34; is declared but not used" source="unused_interface_methods"></error>
+	</file>
+</checkstyle>
+`},
+		{"junit", `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="unused_interface_methods" tests="3" failures="3">
+	<testsuite name="example.com/f/app" tests="1" failures="1">
+		<testcase name="cache.Evict" classname="example.com/f/app.cache">
+			<failure message="method &#34;Evict&#34; of interface &#34;cache&#34; is declared but not used" type="error">app/app.go:6:2: method &#34;Evict&#34; of interface &#34;cache&#34; is declared but not used</failure>
+		</testcase>
+	</testsuite>
+	<testsuite name="example.com/f/store" tests="2" failures="2">
+		<testcase name="Store.Delete" classname="example.com/f/store.Store">
+			<failure message="method &#34;Delete&#34; of interface &#34;Store&#34; is declared but not used" type="error">store/store.go:6:2: method &#34;Delete&#34; of interface &#34;Store&#34; is declared but not used</failure>
+		</testcase>
+		<testcase name="Store.Put" classname="example.com/f/store.Store">
+			<failure message="method &#34;Put&#34; of interface &#34;Store&#34; is declared but not used" type="error">store/store.go:5:2: method &#34;Put&#34; of interface &#34;Store&#34; is declared but not used</failure>
+		</testcase>
+	</testsuite>
+</testsuites>
+`},
+		{"github", `::error file=app/app.go,line=6,col=2,title=unused_interface_methods::method "Evict" of interface "cache" is declared but not used
+::error file=store/store.go,line=5,col=2,title=unused_interface_methods::method "Put" of interface "Store" is declared but not used
+::error file=store/store.go,line=6,col=2,title=unused_interface_methods::method "Delete" of interface "Store" is declared but not used
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runDriver([]string{"-format=" + tt.format, "-module", "-cache=false", "-test=false", "./..."}, &stdout, &stderr)
+			if code != exitFindings {
+				t.Errorf("runDriver() = %d, want %d; stderr: %s", code, exitFindings, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("runDriver() output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runDriver([]string{"-format=xml", "./..."}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("runDriver() with an unknown format = %d, want %d", code, exitFailure)
+	}
+}
+
+func TestCodeClimateFormat(t *testing.T) {
+	isolateGoEnv(t)
+	t.Chdir(writeFormatModule(t))
+
+	type issue struct {
+		Type        string   `json:"type"`
+		CheckName   string   `json:"check_name"`
+		Description string   `json:"description"`
+		Categories  []string `json:"categories"`
+		Severity    string   `json:"severity"`
+		Fingerprint string   `json:"fingerprint"`
+		Location    struct {
+			Path  string `json:"path"`
+			Lines struct {
+				Begin int `json:"begin"`
+			} `json:"lines"`
+		} `json:"location"`
+	}
+	run := func() []issue {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := runDriver([]string{"-format=codeclimate", "-module", "-cache=false", "-test=false", "./..."}, &stdout, &stderr); code != exitFindings {
+			t.Fatalf("runDriver() = %d, want %d; stderr: %s", code, exitFindings, stderr.String())
+		}
+		var issues []issue
+		if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+			t.Fatalf("output is not a JSON array of issues: %v\n%s", err, stdout.String())
+		}
+		return issues
+	}
+
+	issues := run()
+	type summary struct {
+		path, description, severity string
+		line                        int
+	}
+	var got []summary
+	fingerprints := make(map[string]bool)
+	for _, is := range issues {
+		if is.Type != "issue" || is.CheckName != a.Name || len(is.Categories) == 0 {
+			t.Errorf("issue %+v lacks type, check name or categories", is)
+		}
+		if len(is.Fingerprint) != 32 || fingerprints[is.Fingerprint] {
+			t.Errorf("fingerprint %q is not a unique 32 digit hash", is.Fingerprint)
+		}
+		fingerprints[is.Fingerprint] = true
+		got = append(got, summary{is.Location.Path, is.Description, is.Severity, is.Location.Lines.Begin})
+	}
+	want := []summary{
+		{"app/app.go", `method "Evict" of interface "cache" is declared but not used`, "major", 6},
+		{"store/store.go", `method "Put" of interface "Store" is declared but not used`, "major", 5},
+		{"store/store.go", `method "Delete" of interface "Store" is declared but not used`, "major", 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("runDriver() issues = %v, want %v", got, want)
+	}
+
+	// Fingerprints survive line shifts
+	storeFile := filepath.Join("store", "store.go")
+	data, err := os.ReadFile(storeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(storeFile, append([]byte("// Package store stores.\n"), data...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	shifted := run()
+	if len(shifted) != len(issues) {
+		t.Fatalf("runDriver() after inserting a line reported %d issues, want %d", len(shifted), len(issues))
+	}
+	for i, is := range shifted {
+		if is.Fingerprint != issues[i].Fingerprint {
+			t.Errorf("fingerprint of %q changed from %s to %s after inserting a line", is.Description, issues[i].Fingerprint, is.Fingerprint)
+		}
+	}
+	if line := shifted[1].Location.Lines.Begin; line != 6 {
+		t.Errorf("line of the shifted issue = %d, want 6", line)
+	}
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	if got, want := escapeGitHubData("100% done\nnext: a,b"), "100%25 done%0Anext: a,b"; got != want {
+		t.Errorf("escapeGitHubData() = %q, want %q", got, want)
+	}
+	if got, want := escapeGitHubProperty("dir,1/a:b.go"), "dir%2C1/a%3Ab.go"; got != want {
+		t.Errorf("escapeGitHubProperty() = %q, want %q", got, want)
+	}
+}
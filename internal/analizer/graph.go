@@ -0,0 +1,344 @@
+package analizer
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/packages"
+)
+
+// graphNodeKind is the kind of a node of the interface graph
+type graphNodeKind int
+
+const (
+	nodeInterface graphNodeKind = iota
+	nodeMethod
+	nodeType // concrete implementation
+	nodeSite // site using a method
+)
+
+// graphEdgeKind is the kind of an edge of the interface graph
+type graphEdgeKind int
+
+const (
+	edgeMethod     graphEdgeKind = iota // interface to its method
+	edgeEmbeds                          // interface to an embedded interface
+	edgeImplements                      // concrete type to the interface it implements
+	edgeUses                            // method to a site using it
+)
+
+type graphNode struct {
+	id     string // unique, a qualified name or a position
+	label  string
+	kind   graphNodeKind
+	unused bool // method reported by a finding, unused or used only from tests
+}
+
+type graphEdge struct {
+	from, to string
+	kind     graphEdgeKind
+}
+
+// interfaceGraph describes interfaces, their methods, embedding relations,
+// implementations and usage sites
+type interfaceGraph struct {
+	nodes []graphNode
+	edges []graphEdge
+	index map[string]int // node position by id
+}
+
+// addNode adds the node unless a node with its id exists
+func (g *interfaceGraph) addNode(node graphNode) {
+	if _, ok := g.index[node.id]; ok {
+		return
+	}
+	g.index[node.id] = len(g.nodes)
+	g.nodes = append(g.nodes, node)
+}
+
+// addEdge adds an edge between existing nodes
+func (g *interfaceGraph) addEdge(from, to string, kind graphEdgeKind) {
+	g.edges = append(g.edges, graphEdge{from: from, to: to, kind: kind})
+}
+
+// RunGraph executes the graph subcommand and exits
+func RunGraph() {
+	os.Exit(runGraph(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runGraph prints the interface graph of the loaded packages, returning the exit code
+func runGraph(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "dot", "output format: dot (Graphviz) or mermaid")
+	pkgPattern := fs.String("pkg", "", "include only interfaces of packages matching the import path pattern, like example.com/app/...")
+	ifacePattern := fs.String("iface", "", "include only interfaces whose name matches the glob pattern, like *Store")
+	sites := fs.Bool("sites", true, "include the sites using each method")
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s graph [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Prints interfaces of the loaded packages (./... by default) with their methods,")
+		fmt.Fprintln(stderr, "embedded interfaces, implementations and usage sites as a Graphviz or Mermaid")
+		fmt.Fprintln(stderr, "graph. Methods reported with the configured severity are highlighted.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+	if *format != "dot" && *format != "mermaid" {
+		fmt.Fprintf(stderr, "%s: invalid format %q, want dot or mermaid\n", a.Name, *format)
+		return exitFailure
+	}
+	if !config.ValidNamePattern(*ifacePattern) {
+		fmt.Fprintf(stderr, "%s: invalid -iface pattern %q\n", a.Name, *ifacePattern)
+		return exitFailure
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	pkgs, results, err := s.analyzeModule(stderr, patterns, *tests, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	include := func(pkgPath, ifaceName string) bool {
+		if *pkgPattern != "" && !config.MatchImportPath(*pkgPattern, pkgPath) {
+			return false
+		}
+		return *ifacePattern == "" || config.MatchName(*ifacePattern, ifaceName)
+	}
+	g := s.buildInterfaceGraph(pkgs, newUsageIndex(results), include, *sites)
+	if *format == "mermaid" {
+		err = printMermaid(stdout, g)
+	} else {
+		err = printDOT(stdout, g)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// buildInterfaceGraph builds the graph of the interfaces accepted by include
+func (s *settings) buildInterfaceGraph(pkgs []*packages.Package, idx *usageIndex, include func(pkgPath, ifaceName string) bool, withSites bool) *interfaceGraph {
+	g := &interfaceGraph{index: make(map[string]int)}
+	concrete, _ := s.concreteTypes(pkgs)
+	qualifier := func(p *types.Package) string { return p.Name() }
+	cwd, _ := os.Getwd()
+
+	for _, named := range s.interfaceTypes(pkgs) {
+		obj := named.Obj()
+		if !include(obj.Pkg().Path(), obj.Name()) {
+			continue
+		}
+		iface := named.Underlying().(*types.Interface)
+		ifaceID := obj.Pkg().Path() + "." + obj.Name()
+		g.addNode(graphNode{id: ifaceID, label: types.TypeString(named, qualifier), kind: nodeInterface})
+		addInterfaceRelations(g, ifaceID, iface, concrete, qualifier)
+
+		for i := 0; i < iface.NumExplicitMethods(); i++ {
+			name := ifaceID + "." + iface.ExplicitMethod(i).Name()
+			// Methods ignored by the configuration are shown but never highlighted,
+			// the others when they are reported with the configured severity
+			m, declared := idx.declared[name]
+			sites := idx.sites[name]
+			used, testUsed := siteUsage(sites)
+			severity, _ := s.unusedSeverity(m, used, testUsed)
+			g.addNode(graphNode{
+				id:     name,
+				label:  iface.ExplicitMethod(i).Name(),
+				kind:   nodeMethod,
+				unused: declared && severity != config.SeverityOff,
+			})
+			g.addEdge(ifaceID, name, edgeMethod)
+			if !withSites {
+				continue
+			}
+			for _, site := range sites {
+				filename := site.Posn.Filename
+				if rel, err := filepath.Rel(cwd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+					filename = rel
+				}
+				id := site.Posn.String()
+				g.addNode(graphNode{id: id, label: fmt.Sprintf("%s:%d", filepath.ToSlash(filename), site.Posn.Line), kind: nodeSite})
+				g.addEdge(name, id, edgeUses)
+			}
+		}
+	}
+	return g
+}
+
+// interfaceTypes returns the named interfaces declared in the packages outside
+// ignored packages and files, sorted by qualified name
+func (s *settings) interfaceTypes(pkgs []*packages.Package) []*types.Named {
+	seen := make(map[string]bool)
+	var result []*types.Named
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || s.cfg.ShouldIgnorePackage(pkg.PkgPath) {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() || !types.IsInterface(obj.Type()) {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			// Test variants of a package declare the same types again
+			if !ok || seen[pkg.PkgPath+"."+name] {
+				continue
+			}
+			seen[pkg.PkgPath+"."+name] = true
+			if s.cfg.ShouldIgnore(s.relativePath(pkg.Fset.Position(obj.Pos()).Filename)) {
+				continue
+			}
+			result = append(result, named)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		pi, pj := result[i].Obj().Pkg().Path(), result[j].Obj().Pkg().Path()
+		if pi != pj {
+			return pi < pj
+		}
+		return result[i].Obj().Name() < result[j].Obj().Name()
+	})
+	return result
+}
+
+// addInterfaceRelations adds the embedded interfaces and the implementations of the interface
+func addInterfaceRelations(g *interfaceGraph, ifaceID string, iface *types.Interface, concrete []*types.Named, qualifier types.Qualifier) {
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		embedded, ok := iface.EmbeddedType(i).(*types.Named)
+		if !ok || !types.IsInterface(embedded) || embedded.Obj().Pkg() == nil {
+			continue
+		}
+		id := embedded.Obj().Pkg().Path() + "." + embedded.Obj().Name()
+		g.addNode(graphNode{id: id, label: types.TypeString(embedded, qualifier), kind: nodeInterface})
+		g.addEdge(ifaceID, id, edgeEmbeds)
+	}
+	seen := make(map[string]bool)
+	for _, t := range implementing(concrete, iface) {
+		// Test variants of a package declare the same types again
+		id := types.TypeString(t, nil)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		g.addNode(graphNode{id: id, label: types.TypeString(t, qualifier), kind: nodeType})
+		g.addEdge(id, ifaceID, edgeImplements)
+	}
+}
+
+// printDOT prints the graph in the Graphviz DOT language
+func printDOT(w io.Writer, g *interfaceGraph) error {
+	var b strings.Builder
+	b.WriteString("digraph interfaces {\n\trankdir=LR;\n\tnode [fontname=\"Helvetica\"];\n")
+	for _, node := range g.nodes {
+		var attrs string
+		switch node.kind {
+		case nodeInterface:
+			attrs = "shape=box, style=bold"
+		case nodeMethod:
+			attrs = "shape=ellipse"
+			if node.unused {
+				attrs += ", color=red, fontcolor=red, style=filled, fillcolor=\"#ffe0e0\""
+			}
+		case nodeType:
+			attrs = "shape=component"
+		case nodeSite:
+			attrs = "shape=note, fontsize=10"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, %s];\n", strconv.Quote(node.id), strconv.Quote(node.label), attrs)
+	}
+	for _, edge := range sortedEdges(g) {
+		var attrs string
+		switch edge.kind {
+		case edgeEmbeds:
+			attrs = " [label=\"embeds\"]"
+		case edgeImplements:
+			attrs = " [label=\"implements\", style=dashed]"
+		case edgeUses:
+			attrs = " [style=dotted]"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", strconv.Quote(edge.from), strconv.Quote(edge.to), attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// printMermaid prints the graph as a Mermaid flowchart
+func printMermaid(w io.Writer, g *interfaceGraph) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	// Mermaid ids cannot contain most punctuation, so nodes are numbered
+	ids := make(map[string]string, len(g.nodes))
+	var unused []string
+	for i, node := range g.nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[node.id] = id
+		label := strings.ReplaceAll(node.label, `"`, "#quot;")
+		switch node.kind {
+		case nodeInterface:
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, label)
+		case nodeMethod:
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", id, label)
+			if node.unused {
+				unused = append(unused, id)
+			}
+		case nodeType:
+			fmt.Fprintf(&b, "  %s[[\"%s\"]]\n", id, label)
+		case nodeSite:
+			fmt.Fprintf(&b, "  %s>\"%s\"]\n", id, label)
+		}
+	}
+	for _, edge := range sortedEdges(g) {
+		arrow := "-->"
+		switch edge.kind {
+		case edgeEmbeds:
+			arrow = "-->|embeds|"
+		case edgeImplements:
+			arrow = "-.->|implements|"
+		case edgeUses:
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[edge.from], arrow, ids[edge.to])
+	}
+	if len(unused) > 0 {
+		b.WriteString("  classDef unused fill:#ffe0e0,stroke:#d00,color:#d00\n")
+		fmt.Fprintf(&b, "  class %s unused\n", strings.Join(unused, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sortedEdges returns the edges ordered by the position of their nodes
+func sortedEdges(g *interfaceGraph) []graphEdge {
+	edges := append([]graphEdge(nil), g.edges...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		if fi, fj := g.index[edges[i].from], g.index[edges[j].from]; fi != fj {
+			return fi < fj
+		}
+		return g.index[edges[i].to] < g.index[edges[j].to]
+	})
+	return edges
+}
@@ -0,0 +1,70 @@
+package analizer
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	root := chdirModule(t, map[string]string{
+		"go.mod":         "module example.com/g\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Reader interface {\n\tGet() string\n}\n\ntype Store interface {\n\tReader\n\tPut()\n}\n\ntype Memory struct{}\n\nfunc (Memory) Get() string { return \"\" }\nfunc (Memory) Put()          {}\n\ntype Cache interface {\n\tEvict()\n}\n",
+		// Methods of Cache are never reported, so they are not highlighted
+		".unused-interface-methods.yml": "severity-rules:\n  - interfaces: [\"Cache\"]\n    level: off\n",
+		"app/app.go":                    "package app\n\nimport \"example.com/g/store\"\n\nfunc Use(r store.Reader) string { return r.Get() }\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := runGraph([]string{"-test=false"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runGraph() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	dot := stdout.String()
+	for _, want := range []string{
+		`"example.com/g/store.Store" -> "example.com/g/store.Reader" [label="embeds"];`,
+		`"example.com/g/store.Memory" -> "example.com/g/store.Store" [label="implements", style=dashed];`,
+		`"example.com/g/store.Store.Put" [label="Put", shape=ellipse, color=red`,
+		`"example.com/g/store.Cache.Evict" [label="Evict", shape=ellipse];`,
+		`"example.com/g/store.Reader.Get" -> "` + filepath.Join(root, "app", "app.go") + `:5:42" [style=dotted];`,
+		`[label="app/app.go:5", shape=note`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("runGraph() DOT output does not contain %s:\n%s", want, dot)
+		}
+	}
+
+	stdout.Reset()
+	if code := runGraph([]string{"-test=false", "-format=mermaid", "-iface=Store", "-sites=false"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runGraph() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	want := strings.Join([]string{
+		"flowchart LR",
+		`  n0["store.Store"]`,
+		`  n1["store.Reader"]`,
+		`  n2[["store.Memory"]]`,
+		`  n3(["Put"])`,
+		"  n0 -->|embeds| n1",
+		"  n0 --> n3",
+		"  n2 -.->|implements| n0",
+		"  classDef unused fill:#ffe0e0,stroke:#d00,color:#d00",
+		"  class n3 unused",
+	}, "\n")
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Errorf("runGraph() Mermaid output:\n%s\nwant:\n%s", got, want)
+	}
+
+	// -iface uses the glob syntax of the configuration
+	stdout.Reset()
+	if code := runGraph([]string{"-test=false", "-iface={Cache,Reader}", "-sites=false"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runGraph() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	dot = stdout.String()
+	if !strings.Contains(dot, `"example.com/g/store.Cache" [`) || !strings.Contains(dot, `"example.com/g/store.Reader" [`) ||
+		strings.Contains(dot, `"example.com/g/store.Store" [`) {
+		t.Errorf("runGraph() -iface={Cache,Reader} output:\n%s\nwant Cache and Reader only", dot)
+	}
+	if code := runGraph([]string{"-iface=[Store"}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("runGraph() with an invalid -iface = %d, want %d", code, exitFailure)
+	}
+}
@@ -0,0 +1,193 @@
+package analizer
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// markRegisteredServers marks all methods of gRPC server interfaces as used.
+// Generated code registers an XxxServer implementation with RegisterXxxServer and
+// the gRPC runtime invokes its methods through a handler table, which is invisible
+// in the AST.
+func (ma *methodAnalyzer) markRegisteredServers() {
+	scope := ma.pass.Pkg.Scope()
+	registered := make(map[string]bool)
+
+	for method, info := range ma.ifaceMethods {
+		if info.foreign {
+			continue // registered in the declaring package
+		}
+		register := scope.Lookup("Register" + info.ifaceName)
+		isRegistered, cached := registered[info.ifaceName]
+		if !cached {
+			isRegistered = registersInterface(register, info.iface)
+			registered[info.ifaceName] = isRegistered
+			if isRegistered && verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] gRPC server registered: %s\n", info.ifaceName)
+			}
+		}
+		if isRegistered {
+			ma.pos = register.Pos()
+			ma.markUsed(method, ruleGRPC)
+		}
+	}
+}
+
+// registersInterface checks if obj is a function taking the interface as a parameter,
+// like RegisterGreeterServer(s grpc.ServiceRegistrar, srv GreeterServer)
+func registersInterface(obj types.Object, iface *types.Interface) bool {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if types.Identical(params.At(i).Type().Underlying(), iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// unusedRPC is a method of a gRPC client interface never called in the loaded packages
+type unusedRPC struct {
+	posn       token.Position
+	clientName string
+	methodName string
+}
+
+// RunUnusedRPCs executes the unused-rpcs subcommand and exits
+func RunUnusedRPCs() {
+	os.Exit(runUnusedRPCs(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runUnusedRPCs reports gRPC client methods nobody calls, returning the exit code
+func runUnusedRPCs(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("unused-rpcs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tests := fs.Bool("test", true, "count calls in test files, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s unused-rpcs [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Lists methods of generated gRPC XxxClient interfaces never called")
+		fmt.Fprintf(stderr, "anywhere in the loaded packages (RPCs nobody uses).\n\n")
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	pkgs, err := loadPackages(patterns, *tests, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	unused := findUnusedRPCs(pkgs)
+	for _, rpc := range unused {
+		fmt.Fprintf(stdout, "%s: RPC %q of client %q is never called\n", rpc.posn, rpc.methodName, rpc.clientName)
+	}
+	if len(unused) > 0 {
+		return exitFindings
+	}
+	return exitOK
+}
+
+// findUnusedRPCs finds methods of gRPC client interfaces that are never selected in
+// the loaded packages. Packages see each other's types through export data, so
+// methods are matched by qualified name instead of object identity.
+func findUnusedRPCs(pkgs []*packages.Package) []unusedRPC {
+	called := make(map[string]bool) // qualified names pkg.Client.Method
+	for _, pkg := range pkgs {
+		for _, sel := range pkg.TypesInfo.Selections {
+			if sel.Kind() != types.MethodVal && sel.Kind() != types.MethodExpr {
+				continue
+			}
+			receivers := append([]types.Type{sel.Recv()}, embeddedFieldTypes(sel)...)
+			for _, recv := range receivers {
+				if name := qualifiedTypeName(recv); name != "" {
+					called[name+"."+sel.Obj().Name()] = true
+				}
+			}
+		}
+	}
+
+	var unused []unusedRPC
+	seen := make(map[string]bool) // test variants share declarations
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !isGRPCClient(scope, obj) {
+				continue
+			}
+			iface := obj.Type().Underlying().(*types.Interface)
+			for i := 0; i < iface.NumExplicitMethods(); i++ {
+				m := iface.ExplicitMethod(i)
+				key := pkg.PkgPath + "." + name + "." + m.Name()
+				if called[key] || seen[key] {
+					continue
+				}
+				seen[key] = true
+				unused = append(unused, unusedRPC{
+					posn:       pkg.Fset.Position(m.Pos()),
+					clientName: name,
+					methodName: m.Name(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		pi, pj := unused[i].posn, unused[j].posn
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Line < pj.Line
+	})
+	return unused
+}
+
+// isGRPCClient checks if the type is an XxxClient interface with a generated
+// NewXxxClient constructor returning it
+func isGRPCClient(scope *types.Scope, obj *types.TypeName) bool {
+	if !strings.HasSuffix(obj.Name(), "Client") || !types.IsInterface(obj.Type()) {
+		return false
+	}
+	fn, ok := scope.Lookup("New" + obj.Name()).(*types.Func)
+	if !ok {
+		return false
+	}
+	results := fn.Type().(*types.Signature).Results()
+	for i := 0; i < results.Len(); i++ {
+		if types.Identical(results.At(i).Type(), obj.Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedTypeName returns "pkg/path.Name" of a named type or a pointer to it
+func qualifiedTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
@@ -0,0 +1,37 @@
+package analizer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzerGRPCServer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, NewAnalyzer(&config.Config{IncludeGenerated: true}), "greeter")
+}
+
+func TestAnalyzerGRPCGenerated(t *testing.T) {
+	// Interfaces of generated gRPC code are skipped without include-generated,
+	// while calls from the generated client still count
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "greetergenerated")
+}
+
+func TestFindUnusedRPCs(t *testing.T) {
+	pkgs := loadTestdata(t, "greeter", "greeterclient")
+
+	unused := findUnusedRPCs(pkgs)
+	if len(unused) != 1 {
+		t.Fatalf("findUnusedRPCs() = %v, want 1 unused RPC", unused)
+	}
+	got := unused[0]
+	if got.clientName != "GreeterClient" || got.methodName != "SayGoodbye" {
+		t.Errorf("findUnusedRPCs() = %s.%s, want GreeterClient.SayGoodbye", got.clientName, got.methodName)
+	}
+	if filepath.Base(got.posn.Filename) != "greeter_grpc.pb.go" || got.posn.Line != 30 {
+		t.Errorf("findUnusedRPCs() position = %s, want greeter_grpc.pb.go:30", got.posn)
+	}
+}
@@ -0,0 +1,49 @@
+package analizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeModule writes the files, by slash-separated path, into a new temporary
+// directory and returns the directory
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, files)
+	return root
+}
+
+// writeFiles writes the files, by slash-separated path relative to root,
+// creating missing directories
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// isolateGoEnv keeps the go command from picking up the go.work and GOFLAGS
+// of the environment for the rest of the test
+func isolateGoEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "")
+}
+
+// chdirModule writes the files of a module like writeModule into a new temporary
+// directory, changes into it with an isolated go environment and returns it
+func chdirModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	isolateGoEnv(t)
+	root := writeModule(t, files)
+	t.Chdir(root)
+	return root
+}
@@ -1,44 +1,45 @@
 package analizer
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
-
-	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"sync"
 )
 
-var (
-	verbose  bool
-	basePath string
-	cfg      *config.Config
-)
+var verbose bool
 
 func init() {
-	var err error
 	val := os.Getenv("UNUSED_INTERFACE_METHODS_VERBOSE")
 	if val == "1" || val == "true" {
 		verbose = true
 	}
-	basePath, err = extractBasePath(os.Args[1:])
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error extracting base path: %v\n", err)
-		os.Exit(1)
-	}
-	cfg, err = config.LoadConfig("")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
-	}
 }
 
-func extractBasePath(args []string) (string, error) {
-	result := "."
-	if len(args) > 0 {
-		result = args[0]
-		result = strings.TrimSuffix(result, "/...")
-		result = strings.TrimPrefix(result, "./")
+// moduleRoots caches module roots by directory, packages are analyzed concurrently
+var moduleRoots = struct {
+	sync.Mutex
+	dirs map[string]string
+}{dirs: make(map[string]string)}
+
+// moduleRoot returns the directory of the nearest go.mod at or above dir,
+// or an empty string if dir is not inside a module
+func moduleRoot(dir string) string {
+	moduleRoots.Lock()
+	defer moduleRoots.Unlock()
+	return findModuleRoot(dir)
+}
+
+// findModuleRoot walks up from dir, caching every visited directory
+func findModuleRoot(dir string) string {
+	if root, cached := moduleRoots.dirs[dir]; cached {
+		return root
+	}
+	root := ""
+	if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+		root = dir
+	} else if parent := filepath.Dir(dir); parent != dir {
+		root = findModuleRoot(parent)
 	}
-	return filepath.Abs(result)
+	moduleRoots.dirs[dir] = root
+	return root
 }
@@ -0,0 +1,33 @@
+package analizer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRelativePath(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":       "module example.com/x\n",
+		"tools/go.mod": "module example.com/x\n",
+	})
+
+	tests := []struct {
+		name     string
+		basePath string
+		filename string
+		want     string
+	}{
+		{"module root", "", "svc/api/api.go", "svc/api/api.go"},
+		{"other directory of the module", "", "lib/store.go", "lib/store.go"},
+		{"nested module", "", "tools/gen/main.go", "gen/main.go"},
+		{"explicit base path", root, "tools/gen/main.go", "tools/gen/main.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &settings{basePath: tt.basePath}
+			if got := s.relativePath(filepath.Join(root, filepath.FromSlash(tt.filename))); got != tt.want {
+				t.Errorf("relativePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,759 @@
+package analizer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/packages"
+)
+
+// JSON-RPC error codes used by the language server
+const (
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+)
+
+// lspMessage is a JSON-RPC 2.0 request, response or notification
+type lspMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+// lspError is the error of a JSON-RPC response
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCodeAction struct {
+	Title       string          `json:"title"`
+	Kind        string          `json:"kind"`
+	Diagnostics []lspDiagnostic `json:"diagnostics,omitempty"`
+	Edit        struct {
+		Changes map[string][]lspTextEdit `json:"changes"`
+	} `json:"edit"`
+}
+
+type lspWorkspaceFolder struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type lspTextDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+// lspServer publishes findings of the module-wide analysis of each workspace folder.
+// Folders are analyzed in the background, so that requests are answered from the
+// last results while saved files are re-analyzed by a watcher per folder.
+type lspServer struct {
+	out   io.Writer
+	outMu sync.Mutex // guards out
+	tests bool
+	tags  string
+	wake  chan struct{} // signals pending analysis to the analysis goroutine
+	done  chan struct{} // closed when the analysis goroutine returns
+
+	mu        sync.Mutex                     // guards the fields below
+	folders   []*lspFolder                   // workspace folders in the order they were added
+	pending   map[*lspFolder]map[string]bool // changed directories to re-analyze by folder
+	published map[string]bool                // files with published diagnostics
+
+	shutdown bool
+}
+
+// lspFolder is a workspace folder with the results of its module-wide analysis
+type lspFolder struct {
+	root    string
+	watcher *watcher // nil until the folder is loaded, set by the analysis goroutine
+}
+
+// RunLSP executes the lsp subcommand and exits
+func RunLSP() {
+	os.Exit(runLSP(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// runLSP serves the Language Server Protocol over in and out, returning the exit code
+func runLSP(args []string, in io.Reader, out, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s lsp [-flag]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Serves the Language Server Protocol over stdin and stdout: diagnostics")
+		fmt.Fprintln(stderr, "for unused methods, a delete-method code action and usage on hover.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	s := &lspServer{
+		out:       out,
+		tests:     *tests,
+		tags:      *tags,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		pending:   make(map[*lspFolder]map[string]bool),
+		published: make(map[string]bool),
+	}
+	go s.analyze()
+	defer s.stop()
+
+	r := bufio.NewReader(in)
+	for {
+		msg, err := readLSPMessage(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			}
+			return exitFailure
+		}
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return exitOK
+			}
+			return exitFailure
+		}
+		s.handle(msg)
+	}
+}
+
+// readLSPMessage reads one message with its Content-Length header
+func readLSPMessage(r *bufio.Reader) (*lspMessage, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %w", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	var msg lspMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// write sends a message with its Content-Length header
+func (s *lspServer) write(msg *lspMessage) {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+// respond sends the result of a request
+func (s *lspServer) respond(id json.RawMessage, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.respondError(id, lspInvalidParams, err.Error())
+		return
+	}
+	s.write(&lspMessage{ID: id, Result: data})
+}
+
+// respondError sends the error of a request
+func (s *lspServer) respondError(id json.RawMessage, code int, message string) {
+	s.write(&lspMessage{ID: id, Error: &lspError{Code: code, Message: message}})
+}
+
+// notify sends a notification
+func (s *lspServer) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	s.write(&lspMessage{Method: method, Params: data})
+}
+
+// Write sends output of the analysis, like load errors, to the client log
+func (s *lspServer) Write(p []byte) (int, error) {
+	s.notify("window/logMessage", map[string]any{
+		"type":    4, // log
+		"message": strings.TrimRight(string(p), "\n"),
+	})
+	return len(p), nil
+}
+
+// handle dispatches a request or notification
+func (s *lspServer) handle(msg *lspMessage) {
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			RootURI          string               `json:"rootUri"`
+			RootPath         string               `json:"rootPath"`
+			WorkspaceFolders []lspWorkspaceFolder `json:"workspaceFolders"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			s.respondError(msg.ID, lspInvalidParams, err.Error())
+			return
+		}
+		var roots []string
+		switch {
+		case len(params.WorkspaceFolders) > 0:
+			for _, folder := range params.WorkspaceFolders {
+				roots = append(roots, uriToPath(folder.URI))
+			}
+		case params.RootURI != "":
+			roots = []string{uriToPath(params.RootURI)}
+		case params.RootPath != "":
+			roots = []string{params.RootPath}
+		default:
+			if wd, err := os.Getwd(); err == nil {
+				roots = []string{wd}
+			}
+		}
+		s.mu.Lock()
+		for _, root := range roots {
+			s.folders = append(s.folders, &lspFolder{root: root})
+		}
+		s.mu.Unlock()
+		s.respond(msg.ID, map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    0, // files are analyzed from disk on save
+					"save":      map[string]any{"includeText": false},
+				},
+				"hoverProvider":      true,
+				"codeActionProvider": map[string]any{"codeActionKinds": []string{"quickfix"}},
+				"workspace": map[string]any{
+					"workspaceFolders": map[string]any{"supported": true, "changeNotifications": true},
+				},
+			},
+			"serverInfo": map[string]any{"name": a.Name},
+		})
+	case "initialized":
+		s.mu.Lock()
+		for _, folder := range s.folders {
+			s.schedule(folder, nil)
+		}
+		s.mu.Unlock()
+	case "workspace/didChangeWorkspaceFolders":
+		var params struct {
+			Event struct {
+				Added   []lspWorkspaceFolder `json:"added"`
+				Removed []lspWorkspaceFolder `json:"removed"`
+			} `json:"event"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			s.changeFolders(params.Event.Added, params.Event.Removed)
+		}
+	case "textDocument/didSave":
+		var params lspTextDocumentPosition
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			s.update([]string{uriToPath(params.TextDocument.URI)})
+		}
+	case "workspace/didChangeWatchedFiles":
+		var params struct {
+			Changes []struct {
+				URI string `json:"uri"`
+			} `json:"changes"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			var paths []string
+			for _, change := range params.Changes {
+				if path := uriToPath(change.URI); strings.HasSuffix(path, ".go") {
+					paths = append(paths, path)
+				}
+			}
+			s.update(paths)
+		}
+	case "textDocument/hover":
+		var params lspTextDocumentPosition
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			s.respondError(msg.ID, lspInvalidParams, err.Error())
+			return
+		}
+		s.respond(msg.ID, s.hover(uriToPath(params.TextDocument.URI), params.Position))
+	case "textDocument/codeAction":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			Range lspRange `json:"range"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			s.respondError(msg.ID, lspInvalidParams, err.Error())
+			return
+		}
+		s.respond(msg.ID, s.codeActions(params.TextDocument.URI, params.Range))
+	case "shutdown":
+		s.shutdown = true
+		s.respond(msg.ID, nil)
+	default:
+		if msg.ID != nil {
+			s.respondError(msg.ID, lspMethodNotFound, "method not supported: "+msg.Method)
+		}
+	}
+}
+
+// changeFolders adds and removes workspace folders, clearing diagnostics of removed folders
+func (s *lspServer) changeFolders(added, removed []lspWorkspaceFolder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, folder := range removed {
+		root := uriToPath(folder.URI)
+		for i, f := range s.folders {
+			if f.root == root {
+				s.folders = append(s.folders[:i], s.folders[i+1:]...)
+				delete(s.pending, f)
+				break
+			}
+		}
+	}
+	for _, folder := range added {
+		f := &lspFolder{root: uriToPath(folder.URI)}
+		s.folders = append(s.folders, f)
+		s.schedule(f, nil)
+	}
+	s.publish()
+}
+
+// folderOf returns the innermost workspace folder containing the file, or nil
+func (s *lspServer) folderOf(filename string) *lspFolder {
+	var folder *lspFolder
+	for _, f := range s.folders {
+		if filename != f.root && !strings.HasPrefix(filename, f.root+string(filepath.Separator)) {
+			continue
+		}
+		if folder == nil || len(f.root) > len(folder.root) {
+			folder = f
+		}
+	}
+	return folder
+}
+
+// update schedules re-analyzing the directories of the changed files in their folders
+func (s *lspServer) update(paths []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range paths {
+		if folder := s.folderOf(path); folder != nil {
+			s.schedule(folder, []string{filepath.Dir(path)})
+		}
+	}
+}
+
+// schedule queues analyzing the directories of the folder, or the whole folder
+// when dirs is empty, and wakes up the analysis goroutine. s.mu must be held.
+func (s *lspServer) schedule(folder *lspFolder, dirs []string) {
+	changed, ok := s.pending[folder]
+	if !ok {
+		changed = make(map[string]bool)
+		s.pending[folder] = changed
+	}
+	for _, dir := range dirs {
+		changed[dir] = true
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default: // already signaled
+	}
+}
+
+// analyze runs queued analysis until the server stops. Loading and analyzing
+// packages happen without holding s.mu, so that requests are answered meanwhile.
+func (s *lspServer) analyze() {
+	defer close(s.done)
+	for range s.wake {
+		for {
+			s.mu.Lock()
+			var folder *lspFolder
+			var changed map[string]bool
+			for f, dirs := range s.pending {
+				folder, changed = f, dirs
+				break
+			}
+			delete(s.pending, folder)
+			s.mu.Unlock()
+			if folder == nil {
+				break
+			}
+
+			if folder.watcher == nil {
+				s.load(folder)
+				continue
+			}
+			dirs := make([]string, 0, len(changed))
+			for dir := range changed {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			change, ok := folder.watcher.reanalyze(dirs)
+			if !ok {
+				continue
+			}
+			s.mu.Lock()
+			folder.watcher.apply(change)
+			s.publish()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// stop discards queued analysis and waits for the analysis goroutine to return
+func (s *lspServer) stop() {
+	s.mu.Lock()
+	clear(s.pending)
+	s.mu.Unlock()
+	close(s.wake)
+	<-s.done
+}
+
+// load analyzes all packages of the workspace folder with its own configuration
+// and publishes diagnostics
+func (s *lspServer) load(folder *lspFolder) {
+	cfg, err := config.LoadConfigFrom(folder.root)
+	if err != nil {
+		fmt.Fprintf(s, "%s: loading config: %v\n", a.Name, err)
+		return
+	}
+	settings := &settings{cfg: cfg, sites: true} // sites for hover
+
+	pkgs, err := loadPackagesIn(folder.root, []string{"./..."}, s.tests, s.tags)
+	if err != nil {
+		fmt.Fprintf(s, "%s: %v\n", a.Name, err)
+		return
+	}
+	settings.moduleScope = newModuleScope(pkgs)
+	results, err := settings.analyzePackages(pkgs)
+	if err != nil {
+		fmt.Fprintf(s, "%s: %v\n", a.Name, err)
+		return
+	}
+	watcher := newWatcher(settings, folder.root, s, results, s.tests, s.tags, true, nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder.watcher = watcher
+	s.publish()
+}
+
+// publish sends diagnostics of all files with findings in loaded folders and
+// clears files without findings. s.mu must be held.
+func (s *lspServer) publish() {
+	files := make(lspFiles)
+	byFile := make(map[string][]lspDiagnostic)
+	for _, folder := range s.folders {
+		if folder.watcher == nil {
+			continue
+		}
+		for _, f := range folder.watcher.findings {
+			byFile[f.Posn.Filename] = append(byFile[f.Posn.Filename], findingDiagnostic(files, f))
+		}
+	}
+	for filename := range s.published {
+		if _, ok := byFile[filename]; !ok {
+			byFile[filename] = []lspDiagnostic{}
+		}
+	}
+
+	filenames := make([]string, 0, len(byFile))
+	for filename := range byFile {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	for _, filename := range filenames {
+		diagnostics := byFile[filename]
+		sort.Slice(diagnostics, func(i, j int) bool {
+			return diagnostics[i].Range.Start.Line < diagnostics[j].Range.Start.Line
+		})
+		s.notify("textDocument/publishDiagnostics", map[string]any{
+			"uri":         pathToURI(filename),
+			"diagnostics": diagnostics,
+		})
+		if len(diagnostics) > 0 {
+			s.published[filename] = true
+		} else {
+			delete(s.published, filename)
+		}
+	}
+}
+
+// lspFiles holds the content of files read to convert positions, by file name
+type lspFiles map[string][]byte
+
+// position converts a position to an LSP position, whose character is counted
+// in UTF-16 code units. Columns of the position count bytes.
+func (files lspFiles) position(posn token.Position) lspPosition {
+	src, ok := files[posn.Filename]
+	if !ok {
+		src, _ = os.ReadFile(posn.Filename)
+		files[posn.Filename] = src
+	}
+	lineStart := posn.Offset - (posn.Column - 1)
+	if lineStart < 0 || posn.Offset > len(src) {
+		// The file changed since it was analyzed: best effort
+		return lspPosition{Line: posn.Line - 1, Character: posn.Column - 1}
+	}
+	return lspPosition{Line: posn.Line - 1, Character: len(utf16.Encode([]rune(string(src[lineStart:posn.Offset]))))}
+}
+
+// findingDiagnostic converts a finding to an LSP diagnostic on the method name
+func findingDiagnostic(files lspFiles, f finding) lspDiagnostic {
+	start := files.position(f.Posn)
+	end := start
+	if f.End.IsValid() {
+		end = files.position(f.End)
+	}
+	severity := 1
+	switch f.Severity {
+	case config.SeverityWarning:
+		severity = 2
+	case config.SeverityInfo:
+		severity = 3
+	}
+	return lspDiagnostic{
+		Range:    lspRange{Start: start, End: end},
+		Severity: severity,
+		Source:   a.Name,
+		Message:  f.Message,
+	}
+}
+
+// hover explains the usage of the interface method declared at the position,
+// returning nil elsewhere
+func (s *lspServer) hover(filename string, pos lspPosition) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	folder := s.folderOf(filename)
+	if folder == nil || folder.watcher == nil {
+		return nil
+	}
+	results := folder.watcher.sortedResults()
+
+	files := make(lspFiles)
+	var method *declaredMethod
+	var rng lspRange
+	var pkgs []*packages.Package
+	for _, result := range results {
+		if result.usage == nil {
+			continue
+		}
+		pkgs = append(pkgs, result.pkg)
+		for i, m := range result.usage.Declared {
+			if m.Posn.Filename != filename || m.Posn.Line-1 != pos.Line {
+				continue
+			}
+			start, end := files.position(m.Posn), files.position(m.end())
+			if pos.Character >= start.Character && pos.Character < end.Character {
+				method = &result.usage.Declared[i]
+				rng = lspRange{Start: start, End: end}
+			}
+		}
+	}
+	if method == nil {
+		return nil
+	}
+	// The index drops sites found again by each package declaring or importing the interface
+	uses := len(newUsageIndex(results).sites[method.qualifiedName()])
+
+	var implementations []string
+	if iface, declPkg := lookupInterface(pkgs, method.PkgPath, method.Iface); iface != nil {
+		concrete, _ := folder.watcher.settings.concreteTypes(pkgs)
+		implementations = implementationNames(concrete, iface, declPkg)
+	}
+
+	text := fmt.Sprintf("method used %d %s; %d %s", uses, plural(uses, "time", "times"),
+		len(implementations), plural(len(implementations), "implementation", "implementations"))
+	if len(implementations) > 0 {
+		text += ": " + strings.Join(implementations, ", ")
+	}
+	return map[string]any{
+		"contents": map[string]any{"kind": "markdown", "value": text},
+		"range":    rng,
+	}
+}
+
+// lookupInterface finds the interface declared in the package with the import path
+func lookupInterface(pkgs []*packages.Package, pkgPath, name string) (*types.Interface, *types.Package) {
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.Types.Path() != pkgPath {
+			continue
+		}
+		if obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName); ok {
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+				return iface, pkg.Types
+			}
+		}
+	}
+	return nil, nil
+}
+
+// plural returns the word form for the count
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// codeActions offers deleting unused methods reported in the range
+func (s *lspServer) codeActions(uri string, rng lspRange) []lspCodeAction {
+	actions := []lspCodeAction{}
+	filename := uriToPath(uri)
+	s.mu.Lock()
+	folder := s.folderOf(filename)
+	if folder == nil || folder.watcher == nil {
+		s.mu.Unlock()
+		return actions
+	}
+	var findings []finding
+	for _, f := range folder.watcher.findings {
+		line := f.Posn.Line - 1
+		if f.Posn.Filename == filename && line >= rng.Start.Line && line <= rng.End.Line {
+			findings = append(findings, f)
+		}
+	}
+	s.mu.Unlock()
+	sortFindings(findings)
+
+	files := make(lspFiles)
+	for _, f := range findings {
+		name, edit, ok := deleteMethodEdit(filename, f.Posn.Line, f.Posn.Column)
+		if !ok {
+			continue
+		}
+		action := lspCodeAction{
+			Title:       fmt.Sprintf("Delete method %s", name),
+			Kind:        "quickfix",
+			Diagnostics: []lspDiagnostic{findingDiagnostic(files, f)},
+		}
+		action.Edit.Changes = map[string][]lspTextEdit{uri: {edit}}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// deleteMethodEdit returns the edit deleting the lines of the interface method
+// whose name is at line and column, including its doc and line comments
+func deleteMethodEdit(filename string, line, column int) (string, lspTextEdit, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return "", lspTextEdit{}, false
+	}
+
+	var name string
+	var edit lspTextEdit
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		iface, ok := n.(*ast.InterfaceType)
+		if !ok || found {
+			return !found
+		}
+		for _, field := range iface.Methods.List {
+			if len(field.Names) != 1 {
+				continue
+			}
+			posn := fset.Position(field.Names[0].Pos())
+			if posn.Line != line || posn.Column != column {
+				continue
+			}
+			start, end := field.Pos(), field.End()
+			if field.Doc != nil {
+				start = field.Doc.Pos()
+			}
+			if field.Comment != nil {
+				end = field.Comment.End()
+			}
+			startLine, endLine := fset.Position(start).Line, fset.Position(end).Line
+			// Deleting whole lines is safe only when the method has lines of its own
+			if fset.Position(iface.Methods.Opening).Line >= startLine || fset.Position(iface.Methods.Closing).Line <= endLine {
+				return false
+			}
+			name = field.Names[0].Name
+			edit = lspTextEdit{Range: lspRange{
+				Start: lspPosition{Line: startLine - 1},
+				End:   lspPosition{Line: endLine},
+			}}
+			found = true
+			return false
+		}
+		return true
+	})
+	return name, edit, found
+}
+
+// uriToPath converts a file URI to a file path
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		path = strings.TrimPrefix(path, "/") // /C:/src -> C:/src
+	}
+	return filepath.FromSlash(path)
+}
+
+// pathToURI converts a file path to a file URI
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // C:/src -> /C:/src
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
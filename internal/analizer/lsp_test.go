@@ -0,0 +1,203 @@
+package analizer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lspClient drives a language server running in the background
+type lspClient struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	msgs   chan *lspMessage // messages sent by the server
+	code   chan int         // exit code of the server
+	stderr bytes.Buffer
+}
+
+// startLSP runs the language server with the arguments until the client exits
+func startLSP(t *testing.T, args ...string) *lspClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &lspClient{t: t, in: inW, msgs: make(chan *lspMessage, 100), code: make(chan int, 1)}
+	go func() {
+		c.code <- runLSP(args, inR, outW, &c.stderr)
+		outW.Close()
+	}()
+	go func() {
+		defer close(c.msgs)
+		r := bufio.NewReader(outR)
+		for {
+			msg, err := readLSPMessage(r)
+			if err != nil {
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	return c
+}
+
+// send sends a request, or a notification when id is 0
+func (c *lspClient) send(id int, method string, params any) {
+	c.t.Helper()
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id > 0 {
+		msg["id"] = id
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// next returns the next message sent by the server, failing after a timeout
+func (c *lspClient) next() *lspMessage {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.msgs:
+		if !ok {
+			c.t.Fatalf("server closed the connection; stderr: %s", c.stderr.String())
+		}
+		return msg
+	case <-time.After(time.Minute):
+		c.t.Fatal("timed out waiting for the server")
+		return nil
+	}
+}
+
+// receive returns the next message, keeping diagnostics it publishes
+func (c *lspClient) receive(diagnostics map[string][]lspDiagnostic) *lspMessage {
+	c.t.Helper()
+	msg := c.next()
+	if msg.Method == "textDocument/publishDiagnostics" {
+		var params struct {
+			URI         string          `json:"uri"`
+			Diagnostics []lspDiagnostic `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		diagnostics[params.URI] = params.Diagnostics
+	}
+	return msg
+}
+
+// result waits for the response to the request with the id
+func (c *lspClient) result(id int, diagnostics map[string][]lspDiagnostic) json.RawMessage {
+	c.t.Helper()
+	for {
+		if msg := c.receive(diagnostics); string(msg.ID) == strconv.Itoa(id) {
+			return msg.Result
+		}
+	}
+}
+
+// waitDiagnostics waits until diagnostics are published for all the files
+func (c *lspClient) waitDiagnostics(diagnostics map[string][]lspDiagnostic, uris ...string) {
+	c.t.Helper()
+	for _, uri := range uris {
+		for {
+			if _, ok := diagnostics[uri]; ok {
+				break
+			}
+			c.receive(diagnostics)
+		}
+	}
+}
+
+func TestLSPSession(t *testing.T) {
+	isolateGoEnv(t)
+	root := writeModule(t, map[string]string{
+		"l/go.mod":         "module example.com/l\n\ngo 1.24\n",
+		"l/store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\t// Put stores.\n\tPut()\n}\n\ntype Memory struct{}\n\nfunc (Memory) Get() string { return \"\" }\nfunc (Memory) Put()          {}\n",
+		"l/app/app.go":     "package app\n\nimport \"example.com/l/store\"\n\nfunc Use(s store.Store) string { return s.Get() + s.Get() }\n",
+		"m/go.mod":         "module example.com/m\n\ngo 1.24\n",
+		"m/cafe/cafe.go":   "package cafe\n\ntype Café interface{ Get() string; Drop() }\n\nfunc Use(c Café) string { return c.Get() }\n",
+	})
+	storeURI := pathToURI(filepath.Join(root, "l", "store", "store.go"))
+	cafeURI := pathToURI(filepath.Join(root, "m", "cafe", "cafe.go"))
+
+	c := startLSP(t, "-test=false")
+	c.send(1, "initialize", map[string]any{"workspaceFolders": []lspWorkspaceFolder{
+		{URI: pathToURI(filepath.Join(root, "l")), Name: "l"},
+		{URI: pathToURI(filepath.Join(root, "m")), Name: "m"},
+	}})
+	diagnostics := make(map[string][]lspDiagnostic)
+	c.result(1, diagnostics)
+	c.send(0, "initialized", map[string]any{})
+	c.waitDiagnostics(diagnostics, storeURI, cafeURI)
+
+	wantRange := lspRange{Start: lspPosition{Line: 5, Character: 1}, End: lspPosition{Line: 5, Character: 4}}
+	if got := diagnostics[storeURI]; len(got) != 1 || got[0].Range != wantRange || got[0].Severity != 1 ||
+		got[0].Message != `method "Put" of interface "Store" is declared but not used` {
+		t.Errorf("published diagnostics = %+v, want one error on Put", got)
+	}
+	// Characters count UTF-16 code units: é takes two bytes but one unit
+	wantRange = lspRange{Start: lspPosition{Line: 2, Character: 35}, End: lspPosition{Line: 2, Character: 39}}
+	if got := diagnostics[cafeURI]; len(got) != 1 || got[0].Range != wantRange {
+		t.Errorf("published diagnostics = %+v, want one error on Drop at %+v", got, wantRange)
+	}
+
+	textDocument := map[string]any{"uri": storeURI}
+	c.send(2, "textDocument/hover", map[string]any{"textDocument": textDocument, "position": lspPosition{Line: 3, Character: 2}})
+	var hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(c.result(2, diagnostics), &hover); err != nil {
+		t.Fatal(err)
+	}
+	if want := "method used 2 times; 1 implementation: Memory"; hover.Contents.Value != want {
+		t.Errorf("hover = %q, want %q", hover.Contents.Value, want)
+	}
+
+	c.send(3, "textDocument/codeAction", map[string]any{"textDocument": textDocument, "range": lspRange{Start: lspPosition{Line: 5}, End: lspPosition{Line: 5}}})
+	var actions []lspCodeAction
+	if err := json.Unmarshal(c.result(3, diagnostics), &actions); err != nil {
+		t.Fatal(err)
+	}
+	wantEdit := lspTextEdit{Range: lspRange{Start: lspPosition{Line: 4}, End: lspPosition{Line: 6}}}
+	if len(actions) != 1 || actions[0].Title != "Delete method Put" ||
+		len(actions[0].Edit.Changes[storeURI]) != 1 || actions[0].Edit.Changes[storeURI][0] != wantEdit {
+		t.Errorf("code actions = %+v, want deleting lines 5-6", actions)
+	}
+
+	// Removing a folder clears its diagnostics
+	c.send(0, "workspace/didChangeWorkspaceFolders", map[string]any{"event": map[string]any{
+		"added":   []lspWorkspaceFolder{},
+		"removed": []lspWorkspaceFolder{{URI: pathToURI(filepath.Join(root, "m")), Name: "m"}},
+	}})
+	c.send(4, "shutdown", nil)
+	c.result(4, diagnostics)
+	if got := diagnostics[cafeURI]; len(got) != 0 {
+		t.Errorf("diagnostics after removing the folder = %+v, want none", got)
+	}
+	c.send(0, "exit", nil)
+	if code := <-c.code; code != exitOK {
+		t.Fatalf("runLSP() = %d, want %d; stderr: %s", code, exitOK, c.stderr.String())
+	}
+}
+
+func TestURIPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a b", "c.go")
+	uri := pathToURI(path)
+	if !strings.HasPrefix(uri, "file:///") || strings.Contains(uri, " ") {
+		t.Errorf("pathToURI(%q) = %q", path, uri)
+	}
+	if got := uriToPath(uri); got != path {
+		t.Errorf("uriToPath(%q) = %q, want %q", uri, got, path)
+	}
+}
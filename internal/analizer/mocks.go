@@ -0,0 +1,122 @@
+package analizer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// mockGenerators are generator names found in "// Code generated by ..." headers of mock files
+var mockGenerators = []string{"mockgen", "mockery", "moq"}
+
+// mockPackages are import paths of mocking libraries whose types generated mocks embed or refer to
+var mockPackages = map[string]bool{
+	"go.uber.org/mock/gomock":          true,
+	"github.com/golang/mock/gomock":    true,
+	"github.com/stretchr/testify/mock": true,
+}
+
+// mockDetector recognizes generated mock types together with their recorders
+// and expectation helpers (gomock EXPECT(), mockery On()/EXPECT(), moq *Calls()).
+// Calls on such types are expectation setups, neither usage nor implementation.
+type mockDetector struct {
+	fset  *token.FileSet
+	files map[string]bool // Cache mock file flag by filename
+	types map[*types.TypeName]bool
+}
+
+// newMockDetector creates a mock detector for types positioned in fset
+func newMockDetector(fset *token.FileSet) *mockDetector {
+	return &mockDetector{
+		fset:  fset,
+		files: make(map[string]bool),
+		types: make(map[*types.TypeName]bool),
+	}
+}
+
+// isMock checks if the type or the type it points to is a generated mock
+func (md *mockDetector) isMock(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	obj := named.Origin().Obj()
+	if mock, cached := md.types[obj]; cached {
+		return mock
+	}
+	mock := md.isMockFile(md.fset.Position(obj.Pos()).Filename) || md.hasMockFields(named)
+	md.types[obj] = mock
+	return mock
+}
+
+// hasMockFields checks if a struct refers to a mocking library, like gomock mocks
+// with *gomock.Controller. Structs holding other mocks, like test harnesses, are
+// not mocks themselves; recorders are found by their mock file header instead.
+func (md *mockDetector) hasMockFields(named *types.Named) bool {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		fieldType := st.Field(i).Type()
+		if ptr, ok := fieldType.(*types.Pointer); ok {
+			fieldType = ptr.Elem()
+		}
+		fieldNamed, ok := fieldType.(*types.Named)
+		if !ok || fieldNamed.Obj().Pkg() == nil {
+			continue
+		}
+		if mockPackages[fieldNamed.Obj().Pkg().Path()] {
+			return true
+		}
+	}
+	return false
+}
+
+// isMockFile checks if the file has a "Code generated" header of a known mock generator
+func (md *mockDetector) isMockFile(filename string) bool {
+	if filename == "" {
+		return false
+	}
+	if mock, cached := md.files[filename]; cached {
+		return mock
+	}
+
+	mock := false
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err == nil {
+		mock = isMockHeader(file)
+	}
+	md.files[filename] = mock
+	return mock
+}
+
+// isMockHeader checks if a parsed file is generated by a known mock generator
+func isMockHeader(file *ast.File) bool {
+	if !ast.IsGenerated(file) {
+		return false
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			text := strings.ToLower(comment.Text)
+			if !strings.Contains(text, "code generated") {
+				continue
+			}
+			for _, generator := range mockGenerators {
+				if strings.Contains(text, generator) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
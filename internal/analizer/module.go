@@ -0,0 +1,313 @@
+package analizer
+
+import (
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// packageUsage is the analyzer result for one package, keyed by qualified method name.
+// It holds no type information, so it can be stored in the cache.
+type packageUsage struct {
+	Declared []declaredMethod       `json:"declared,omitempty"` // interface methods declared in the package
+	Used     map[string]bool        `json:"used,omitempty"`     // methods used from regular files, including imported ones
+	TestUsed map[string]bool        `json:"testUsed,omitempty"` // methods used from test files, including imported ones
+	Sites    map[string][]usageSite `json:"sites,omitempty"`    // sites using each method, sorted by position
+	Findings []finding              `json:"findings,omitempty"` // findings of per-package analysis
+}
+
+// usageSite is a position using an interface method and the rule that matched it
+type usageSite struct {
+	Posn token.Position `json:"posn"`
+	Rule usageRule      `json:"rule"`
+	Test bool           `json:"test,omitempty"` // in a test file
+}
+
+// declaredMethod is an interface method declared in the analyzed package
+type declaredMethod struct {
+	PkgPath  string         `json:"pkg"`
+	Iface    string         `json:"iface"`
+	Method   string         `json:"method"`
+	Posn     token.Position `json:"posn"`
+	Contract bool           `json:"contract,omitempty"`
+}
+
+// qualifiedName returns the method name qualified with its package and interface
+func (m declaredMethod) qualifiedName() string {
+	return m.PkgPath + "." + m.Iface + "." + m.Method
+}
+
+// end returns the position after the method name
+func (m declaredMethod) end() token.Position {
+	end := m.Posn
+	end.Offset += len(m.Method)
+	end.Column += len(m.Method)
+	return end
+}
+
+// newPackageUsage converts the usage of method objects to the usage of qualified names
+func (s *settings) newPackageUsage(fset *token.FileSet, ifaceMethods map[*types.Func]methodInfo, used, testUsed map[*types.Func]bool, sites map[*types.Func]map[token.Pos]usageRule) *packageUsage {
+	usage := &packageUsage{
+		Used:     make(map[string]bool, len(used)),
+		TestUsed: make(map[string]bool, len(testUsed)),
+		Sites:    make(map[string][]usageSite, len(sites)),
+	}
+	for _, info := range ifaceMethods {
+		if info.foreign {
+			continue
+		}
+		usage.Declared = append(usage.Declared, declaredMethod{
+			PkgPath:  info.pkgPath,
+			Iface:    info.ifaceName,
+			Method:   info.method.Name(),
+			Posn:     fset.Position(info.method.Pos()),
+			Contract: info.contract,
+		})
+	}
+	sort.Slice(usage.Declared, func(i, j int) bool {
+		return usage.Declared[i].qualifiedName() < usage.Declared[j].qualifiedName()
+	})
+	for m := range used {
+		if info, ok := ifaceMethods[m]; ok {
+			usage.Used[info.qualifiedName()] = true
+		}
+	}
+	for m := range testUsed {
+		if info, ok := ifaceMethods[m]; ok {
+			usage.TestUsed[info.qualifiedName()] = true
+		}
+	}
+	for m, rules := range sites {
+		info, ok := ifaceMethods[m]
+		if !ok {
+			continue
+		}
+		name := info.qualifiedName()
+		for pos, rule := range rules {
+			posn := fset.Position(pos)
+			usage.Sites[name] = append(usage.Sites[name], usageSite{
+				Posn: posn,
+				Rule: rule,
+				Test: posn.IsValid() && s.cfg.IsTestFile(s.relativePath(posn.Filename)),
+			})
+		}
+		sortSites(usage.Sites[name])
+	}
+	return usage
+}
+
+// sortSites sorts usage sites by position
+func sortSites(sites []usageSite) {
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].Posn.Filename != sites[j].Posn.Filename {
+			return sites[i].Posn.Filename < sites[j].Posn.Filename
+		}
+		return sites[i].Posn.Offset < sites[j].Posn.Offset
+	})
+}
+
+// analyzeModule loads the packages and analyzes them in module mode for the
+// subcommands reporting on usage, printing packages that failed to w
+func (s *settings) analyzeModule(w io.Writer, patterns []string, tests bool, tags string) ([]*packages.Package, []*packageResult, error) {
+	pkgs, err := loadPackages(patterns, tests, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Usage is tracked in every package importing the declaring one
+	s.moduleScope = newModuleScope(pkgs)
+	s.sites = true
+	results, err := s.analyzePackages(pkgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, result := range results {
+		if result.err != nil {
+			reportResultError(w, result)
+		}
+	}
+	return pkgs, results, nil
+}
+
+// usageIndex merges the declared methods and the usage sites of all analyzed packages
+type usageIndex struct {
+	declared map[string]declaredMethod  // by qualified name
+	sites    map[string][]usageSite     // sites using each method, sorted by position
+	packages map[string]map[string]bool // import paths of the packages using each method
+}
+
+// newUsageIndex builds the index from the results of successfully analyzed packages
+func newUsageIndex(results []*packageResult) *usageIndex {
+	idx := &usageIndex{
+		declared: make(map[string]declaredMethod),
+		sites:    make(map[string][]usageSite),
+		packages: make(map[string]map[string]bool),
+	}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		for _, m := range result.usage.Declared {
+			if _, ok := idx.declared[m.qualifiedName()]; !ok {
+				idx.declared[m.qualifiedName()] = m
+			}
+		}
+		for name, sites := range result.usage.Sites {
+			for _, site := range sites {
+				// Packages recompiled for the tests of a dependency are analyzed twice
+				key := name + " " + site.Posn.String()
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				idx.sites[name] = append(idx.sites[name], site)
+				if idx.packages[name] == nil {
+					idx.packages[name] = make(map[string]bool)
+				}
+				idx.packages[name][result.pkg.PkgPath] = true
+			}
+		}
+	}
+	for _, sites := range idx.sites {
+		sortSites(sites)
+	}
+	return idx
+}
+
+// names returns the qualified names of the declared methods, sorted
+func (idx *usageIndex) names() []string {
+	names := make([]string, 0, len(idx.declared))
+	for name := range idx.declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectImportedInterfaceMethods adds explicit methods of interfaces declared in
+// the module packages imported directly or indirectly by the analyzed package
+func (s *settings) collectImportedInterfaceMethods(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo) {
+	seen := make(map[*types.Package]bool)
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		for _, imp := range pkg.Imports() {
+			if seen[imp] || !s.moduleScope[imp.Path()] {
+				continue
+			}
+			seen[imp] = true
+
+			scope := imp.Scope()
+			for _, name := range scope.Names() {
+				obj, ok := scope.Lookup(name).(*types.TypeName)
+				if !ok || obj.IsAlias() {
+					continue
+				}
+				ifaceType, ok := obj.Type().Underlying().(*types.Interface)
+				if !ok {
+					continue
+				}
+				for i := 0; i < ifaceType.NumExplicitMethods(); i++ {
+					m := ifaceType.ExplicitMethod(i)
+					ifaceMethods[m] = methodInfo{
+						pkgPath:   imp.Path(),
+						ifaceName: name,
+						iface:     ifaceType,
+						method:    m,
+						foreign:   true,
+					}
+				}
+			}
+			visit(imp)
+		}
+	}
+	visit(pass.Pkg)
+}
+
+// unusedSeverity returns the severity and message of the finding reported for the
+// declared method, or SeverityOff if the method is not reported
+func (s *settings) unusedSeverity(m declaredMethod, used, testUsed bool) (config.Severity, string) {
+	if used || m.Contract {
+		return config.SeverityOff, ""
+	}
+	return s.unusedMessage(m.PkgPath, m.Iface, m.Method, testUsed)
+}
+
+// siteUsage tells whether the sites use a method outside test files and in test files
+func siteUsage(sites []usageSite) (used, testUsed bool) {
+	for _, site := range sites {
+		if site.Test {
+			testUsed = true
+		} else {
+			used = true
+		}
+	}
+	return used, testUsed
+}
+
+// newModuleScope returns the import paths of the loaded packages
+func newModuleScope(pkgs []*packages.Package) map[string]bool {
+	scope := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		scope[pkg.PkgPath] = true
+	}
+	return scope
+}
+
+// moduleFindings merges the usage of all analyzed packages into one index and
+// returns findings for methods unused module-wide, with the exit code of failed packages
+func (s *settings) moduleFindings(w io.Writer, results []*packageResult) ([]finding, int) {
+	code := exitOK
+	used := make(map[string]bool)
+	testUsed := make(map[string]bool)
+	declared := make(map[string]declaredMethod)
+	declaredIn := make(map[string]string)
+
+	for _, result := range results {
+		if result.err != nil {
+			reportResultError(w, result)
+			code = exitFailure
+			continue
+		}
+		for name := range result.usage.Used {
+			used[name] = true
+		}
+		for name := range result.usage.TestUsed {
+			testUsed[name] = true
+		}
+		for _, m := range result.usage.Declared {
+			name := m.qualifiedName()
+			if _, ok := declared[name]; !ok {
+				declared[name] = m
+				declaredIn[name] = result.pkg.ID
+			}
+		}
+	}
+
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []finding
+	for _, name := range names {
+		m := declared[name]
+		severity, message := s.unusedSeverity(m, used[name], testUsed[name])
+		if severity == config.SeverityOff {
+			continue
+		}
+		findings = append(findings, finding{
+			PkgID:    declaredIn[name],
+			Posn:     m.Posn,
+			End:      m.end(),
+			Severity: severity,
+			Message:  message,
+		})
+	}
+	return findings, code
+}
@@ -0,0 +1,280 @@
+package analizer
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/packages"
+)
+
+// methodStatus is the status of an interface method shown in the report
+type methodStatus string
+
+const (
+	statusUnused   methodStatus = "unused"
+	statusTestOnly methodStatus = "test-only"
+	statusUsed     methodStatus = "used"
+	statusContract methodStatus = "contract" // unused public contract, never reported
+	statusOff      methodStatus = "off"      // unused or used only from tests, not reported by the severity rules
+)
+
+//go:embed report.html.tmpl
+var reportTemplateText string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportTemplateText))
+
+// report is the data rendered by the HTML report template
+type report struct {
+	Patterns []string
+	Counts   map[methodStatus]int
+	Packages []reportPackage
+}
+
+// Count returns the number of methods with the status
+func (r *report) Count(status string) int {
+	return r.Counts[methodStatus(status)]
+}
+
+type reportPackage struct {
+	Path       string
+	Interfaces []reportInterface
+}
+
+type reportInterface struct {
+	Name            string
+	Location        string
+	Implementations []reportLocation
+	Methods         []reportMethod
+}
+
+type reportMethod struct {
+	Name      string
+	Qualified string
+	Status    methodStatus
+	Location  string
+	Snippet   []snippetLine
+	Sites     []reportSite
+}
+
+type reportSite struct {
+	Location string
+	Rule     usageRule
+	Test     bool
+	Snippet  []snippetLine
+}
+
+// reportLocation is a named declaration with its source
+type reportLocation struct {
+	Name     string
+	Location string
+	Snippet  []snippetLine
+}
+
+type snippetLine struct {
+	Number  int
+	Text    string
+	Current bool // the line of the position
+}
+
+// RunReport executes the report subcommand and exits
+func RunReport() {
+	os.Exit(runReport(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runReport writes a self-contained HTML report of the interface methods, returning the exit code
+func runReport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "", "write the report to the file instead of the standard output")
+	context := fs.Int("context", 2, "lines of source shown around each declaration and site")
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s report [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Writes a static HTML page listing the interfaces of the loaded packages (./... by")
+		fmt.Fprintln(stderr, "default) by package, the status of each method and source snippets of the declarations,")
+		fmt.Fprintln(stderr, "implementations and usage sites.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+	if *context < 0 {
+		fmt.Fprintf(stderr, "%s: invalid -context %d, want a non-negative number of lines\n", a.Name, *context)
+		return exitFailure
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	pkgs, results, err := s.analyzeModule(stderr, patterns, *tests, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	r := s.buildReport(pkgs, newUsageIndex(results), &snippetReader{settings: s, context: *context, files: make(map[string][]string)})
+	r.Patterns = patterns
+	if *output == "" {
+		err = reportTemplate.Execute(stdout, r)
+	} else {
+		err = writeReport(*output, r)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// writeReport renders the report to the file, creating or truncating it
+func writeReport(filename string, r *report) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// buildReport groups the declared methods of the interfaces by package
+func (s *settings) buildReport(pkgs []*packages.Package, idx *usageIndex, src *snippetReader) *report {
+	r := &report{Counts: make(map[methodStatus]int)}
+	if len(pkgs) == 0 {
+		return r
+	}
+	fset := pkgs[0].Fset // shared by all loaded packages
+	concrete, _ := s.concreteTypes(pkgs)
+
+	for _, named := range s.interfaceTypes(pkgs) {
+		obj := named.Obj()
+		iface := named.Underlying().(*types.Interface)
+		ifaceID := obj.Pkg().Path() + "." + obj.Name()
+
+		var methods []reportMethod
+		for i := 0; i < iface.NumExplicitMethods(); i++ {
+			name := ifaceID + "." + iface.ExplicitMethod(i).Name()
+			// Methods ignored by the configuration are left out
+			m, ok := idx.declared[name]
+			if !ok {
+				continue
+			}
+			method := reportMethod{
+				Name:      m.Method,
+				Qualified: name,
+				Status:    s.usageStatus(m, idx.sites[name]),
+				Location:  src.location(m.Posn),
+				Snippet:   src.snippet(m.Posn),
+			}
+			for _, site := range idx.sites[name] {
+				method.Sites = append(method.Sites, reportSite{
+					Location: src.location(site.Posn),
+					Rule:     site.Rule,
+					Test:     site.Test,
+					Snippet:  src.snippet(site.Posn),
+				})
+			}
+			r.Counts[method.Status]++
+			methods = append(methods, method)
+		}
+		if len(methods) == 0 {
+			continue
+		}
+
+		ifacePosn := fset.Position(obj.Pos())
+		ri := reportInterface{Name: obj.Name(), Location: src.location(ifacePosn), Methods: methods}
+		seen := make(map[string]bool)
+		for _, t := range implementing(concrete, iface) {
+			// Test variants of a package declare the same types again
+			typeName := types.TypeString(t, types.RelativeTo(obj.Pkg()))
+			if seen[typeName] {
+				continue
+			}
+			seen[typeName] = true
+			posn := fset.Position(t.Obj().Pos())
+			ri.Implementations = append(ri.Implementations, reportLocation{
+				Name:     typeName,
+				Location: src.location(posn),
+				Snippet:  src.snippet(posn),
+			})
+		}
+
+		if n := len(r.Packages); n == 0 || r.Packages[n-1].Path != obj.Pkg().Path() {
+			r.Packages = append(r.Packages, reportPackage{Path: obj.Pkg().Path()})
+		}
+		last := &r.Packages[len(r.Packages)-1]
+		last.Interfaces = append(last.Interfaces, ri)
+	}
+	return r
+}
+
+// usageStatus classifies a declared method by the sites using it, reporting
+// unused methods with the severity rules like moduleFindings does
+func (s *settings) usageStatus(m declaredMethod, sites []usageSite) methodStatus {
+	used, testUsed := siteUsage(sites)
+	severity, _ := s.unusedSeverity(m, used, testUsed)
+	switch {
+	case used:
+		return statusUsed
+	case m.Contract:
+		return statusContract
+	case severity == config.SeverityOff:
+		return statusOff
+	case testUsed:
+		return statusTestOnly
+	}
+	return statusUnused
+}
+
+// snippetReader reads source lines around positions, caching the files
+type snippetReader struct {
+	settings *settings // resolves relative paths
+	context  int
+	files    map[string][]string
+}
+
+// location returns the position with the file name relative to its module root
+func (s *snippetReader) location(posn token.Position) string {
+	if !posn.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%s:%d", s.settings.relativePath(posn.Filename), posn.Line)
+}
+
+// snippet returns the lines around the position, or nil if the file cannot be read
+func (s *snippetReader) snippet(posn token.Position) []snippetLine {
+	if !posn.IsValid() {
+		return nil
+	}
+	lines, ok := s.files[posn.Filename]
+	if !ok {
+		if data, err := os.ReadFile(posn.Filename); err == nil {
+			lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		}
+		s.files[posn.Filename] = lines
+	}
+	first, last := max(posn.Line-s.context, 1), min(posn.Line+s.context, len(lines))
+	var snippet []snippetLine
+	for n := first; n <= last; n++ {
+		snippet = append(snippet, snippetLine{Number: n, Text: lines[n-1], Current: n == posn.Line})
+	}
+	return snippet
+}
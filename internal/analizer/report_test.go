@@ -0,0 +1,80 @@
+package analizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+)
+
+func TestReport(t *testing.T) {
+	chdirModule(t, map[string]string{
+		"go.mod":         "module example.com/r\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n\tDelete()\n}\n\ntype Memory struct{}\n\nfunc (Memory) Get() string { return \"\" }\nfunc (Memory) Put()          {}\nfunc (Memory) Delete()       {}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/r/store\"\n\nfunc Use(s store.Store) string { return s.Get() + \"<br>\" }\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	output := filepath.Join(t.TempDir(), "report.html")
+	if code := runReport([]string{"-o", output, "-context=1", "-test=false"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runReport() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("runReport() wrote %q to stdout, want the report in %s", stdout.String(), output)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		`<summary>example.com/r/store</summary>`,
+		`<summary>Store<span class="location">store/store.go:3</span></summary>`,
+		`<details class="method" data-name="example.com/r/store.Store.Get" data-status="used">`,
+		`<details class="method" data-name="example.com/r/store.Store.Delete" data-status="unused">`,
+		`<h4>Memory<span class="location">store/store.go:9</span></h4>`,
+		`<h4>direct selection<span class="location">app/app.go:5</span></h4>`,
+		`<span class="current"><i>5</i>func Use(s store.Store) string { return s.Get() &#43; &#34;&lt;br&gt;&#34; }</span>`,
+		`unused 2</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %s", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(html)
+	}
+}
+
+func TestUsageStatus(t *testing.T) {
+	s := defaultSettings(t)
+	s.cfg.SeverityRules = []config.SeverityRule{{Interfaces: []string{"Legacy"}, Level: config.SeverityOff}}
+	tests := []struct {
+		name   string
+		method declaredMethod
+		sites  []usageSite
+		want   methodStatus
+	}{
+		{"no sites", declaredMethod{Iface: "Store"}, nil, statusUnused},
+		{"contract", declaredMethod{Iface: "Store", Contract: true}, nil, statusContract},
+		{"test sites", declaredMethod{Iface: "Store"}, []usageSite{{Test: true}, {Test: true}}, statusTestOnly},
+		{"mixed sites", declaredMethod{Iface: "Store"}, []usageSite{{Test: true}, {}}, statusUsed},
+		{"severity off", declaredMethod{Iface: "Legacy"}, nil, statusOff},
+		{"test sites with severity off", declaredMethod{Iface: "Legacy"}, []usageSite{{Test: true}}, statusOff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.usageStatus(tt.method, tt.sites); got != tt.want {
+				t.Errorf("usageStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	s.cfg.TestOnlySeverity = config.SeverityOff
+	if got := s.usageStatus(declaredMethod{Iface: "Store"}, []usageSite{{Test: true}}); got != statusOff {
+		t.Errorf("usageStatus() with test-only-severity off = %q, want %q", got, statusOff)
+	}
+}
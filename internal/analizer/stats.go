@@ -0,0 +1,129 @@
+package analizer
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// methodStats is the usage of one interface method across the loaded packages
+type methodStats struct {
+	name            string // qualified name, pkg.Iface.Method
+	sites           int    // sites using the method
+	packages        int    // distinct packages using the method
+	implementations int    // concrete types implementing the interface
+	exported        bool   // method of an exported interface, part of the package API
+}
+
+// RunStats executes the stats subcommand and exits
+func RunStats() {
+	os.Exit(runStats(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runStats prints usage statistics of every interface method, returning the exit code
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tests := fs.Bool("test", true, "indicates whether test files should be analyzed, too")
+	tags := fs.String("tags", "", "comma-separated list of build tags")
+	csvOutput := fs.Bool("csv", false, "emit CSV output")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s stats [-flag] [packages]\n\n", a.Name)
+		fmt.Fprintln(stderr, "Lists every interface method of the loaded packages (./... by default) with the")
+		fmt.Fprintln(stderr, "number of sites and packages using it, the number of implementing types of its")
+		fmt.Fprintln(stderr, "interface and whether it is exported.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	s, err := loadSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	pkgs, results, err := s.analyzeModule(stderr, patterns, *tests, *tags)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+
+	stats := s.collectStats(pkgs, newUsageIndex(results))
+	if *csvOutput {
+		err = printStatsCSV(stdout, stats)
+	} else {
+		err = printStatsTable(stdout, stats)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// collectStats computes the statistics of the indexed methods, sorted by name
+func (s *settings) collectStats(pkgs []*packages.Package, idx *usageIndex) []methodStats {
+	concrete, _ := s.concreteTypes(pkgs)
+	implementations := make(map[string]int) // by qualified interface name
+
+	var stats []methodStats
+	for _, name := range idx.names() {
+		m := idx.declared[name]
+		ifaceName := m.PkgPath + "." + m.Iface
+		n, ok := implementations[ifaceName]
+		if !ok {
+			if iface, declPkg := lookupInterface(pkgs, m.PkgPath, m.Iface); iface != nil {
+				n = len(implementationNames(concrete, iface, declPkg))
+			}
+			implementations[ifaceName] = n
+		}
+		stats = append(stats, methodStats{
+			name:            name,
+			sites:           len(idx.sites[name]),
+			packages:        len(idx.packages[name]),
+			implementations: n,
+			exported:        token.IsExported(m.Iface) && token.IsExported(m.Method),
+		})
+	}
+	return stats
+}
+
+// printStatsTable prints the statistics as an aligned table
+func printStatsTable(w io.Writer, stats []methodStats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tSITES\tPACKAGES\tIMPLEMENTATIONS\tEXPORTED")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%t\n", s.name, s.sites, s.packages, s.implementations, s.exported)
+	}
+	return tw.Flush()
+}
+
+// printStatsCSV prints the statistics as CSV with a header row
+func printStatsCSV(w io.Writer, stats []methodStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"method", "sites", "packages", "implementations", "exported"})
+	for _, s := range stats {
+		cw.Write([]string{
+			s.name,
+			strconv.Itoa(s.sites),
+			strconv.Itoa(s.packages),
+			strconv.Itoa(s.implementations),
+			strconv.FormatBool(s.exported),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+package analizer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	chdirModule(t, map[string]string{
+		"go.mod":         "module example.com/s\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n}\n\ntype cache interface {\n\tEvict()\n}\n\ntype Memory struct{}\n\nfunc (Memory) Get() string { return \"\" }\nfunc (Memory) Put()          {}\n\nfunc Read(s Store) string { return s.Get() }\n",
+		"app/app.go":     "package app\n\nimport \"example.com/s/store\"\n\nfunc Use(s store.Store) string { return s.Get() + s.Get() }\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := runStats([]string{"-test=false", "-csv"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runStats() = %d, want %d; stderr: %s", code, exitOK, stderr.String())
+	}
+	want := strings.Join([]string{
+		"method,sites,packages,implementations,exported",
+		"example.com/s/store.Store.Get,3,2,1,true",
+		"example.com/s/store.Store.Put,0,0,1,true",
+		"example.com/s/store.cache.Evict,0,0,0,false",
+	}, "\n")
+	if got := strings.TrimSpace(stdout.String()); got != want {
+		t.Errorf("runStats() output:\n%s\nwant:\n%s", got, want)
+	}
+
+	stdout.Reset()
+	if code := runStats([]string{"-test=false"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("runStats() = %d, want %d", code, exitOK)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], "METHOD ") || strings.Join(strings.Fields(lines[1]), " ") != "example.com/s/store.Store.Get 3 2 1 true" {
+		t.Errorf("runStats() table:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,50 @@
+package contracts
+
+// Plugin is implemented by plugins loaded at runtime.
+//
+//unused:contract
+type Plugin interface {
+	Init() error
+	Shutdown() error
+}
+
+type (
+	// Hook is called by the library.
+	//unused:contract
+	Hook interface {
+		Fire(event string)
+	}
+
+	// Listener is not a contract.
+	Listener interface {
+		Listen() error // want "method \"Listen\" of interface \"Listener\" is declared but not used"
+	}
+
+	// Sink uses the short marker.
+	//contract
+	Sink interface {
+		Write(p []byte) error
+	}
+
+	// contract between the reader and the writer, prose rather than the marker.
+	Agreement interface {
+		Sign() // want "method \"Sign\" of interface \"Agreement\" is declared but not used"
+	}
+)
+
+// Callback is declared as a contract by "contracts.Callback.OnDone".
+type Callback interface {
+	OnDone()
+	OnFail(err error) // want "method \"OnFail\" of interface \"Callback\" is declared but not used"
+}
+
+type noopPlugin struct{}
+
+func (noopPlugin) Init() error     { return nil }
+func (noopPlugin) Shutdown() error { return nil }
+
+type loggingPlugin struct{}
+
+func (*loggingPlugin) Init() error     { return nil }
+func (*loggingPlugin) Shutdown() error { return nil }
+func (*loggingPlugin) OnDone()         {}
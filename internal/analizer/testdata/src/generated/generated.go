@@ -0,0 +1,7 @@
+package generated
+
+// Used only from the generated file
+type Handler interface {
+	Handle(name string) string // used
+	Close()                    // want "method \"Close\" of interface \"Handler\" is declared but not used"
+}
@@ -0,0 +1,18 @@
+// Package mock is a minimal stub of github.com/stretchr/testify/mock for tests.
+package mock
+
+type Arguments []any
+
+func (args Arguments) String(index int) string { return "" }
+
+func (args Arguments) Error(index int) error { return nil }
+
+type Call struct{ Parent *Mock }
+
+func (c *Call) Return(returnArguments ...any) *Call { return c }
+
+type Mock struct{}
+
+func (m *Mock) On(methodName string, arguments ...any) *Call { return &Call{Parent: m} }
+
+func (m *Mock) Called(arguments ...any) Arguments { return nil }
@@ -0,0 +1,18 @@
+package greeter
+
+import "context"
+
+// ServiceRegistrar and ClientConnInterface stand in for the google.golang.org/grpc types
+
+type ServiceRegistrar interface {
+	RegisterService(desc *ServiceDesc, impl any) // want "method \"RegisterService\" of interface \"ServiceRegistrar\" is declared but not used"
+}
+
+type ServiceDesc struct {
+	ServiceName string
+	HandlerType any
+}
+
+type ClientConnInterface interface {
+	Invoke(ctx context.Context, method string, args, reply any) error // used by the generated client
+}
@@ -0,0 +1,19 @@
+package greeterclient
+
+import (
+	"context"
+
+	"greeter"
+)
+
+type app struct {
+	greeter.GreeterClient
+}
+
+func Hello(ctx context.Context, client greeter.GreeterClient) (string, error) {
+	return client.SayHello(ctx, "world")
+}
+
+func (a *app) run(ctx context.Context) {
+	a.SayHello(ctx, "promoted")
+}
@@ -0,0 +1,39 @@
+package mocking
+
+// Case 1: gomock, Get is used in production, Put only in expectations
+type Store interface {
+	Get(key string) (string, error) // used
+	Put(key, value string) error    // want "method \"Put\" of interface \"Store\" is declared but not used"
+}
+
+// Case 2: mockery, Notify is used only in expectations and mock calls
+type Notifier interface {
+	Notify(msg string) error // want "method \"Notify\" of interface \"Notifier\" is declared but not used"
+}
+
+// Case 3: moq, Now is used only through the mock
+type Clock interface {
+	Now() int64 // want "method \"Now\" of interface \"Clock\" is declared but not used"
+}
+
+// Case 4: production implementation is still an implementation
+type Cache interface {
+	Load(key string) string // used through the production implementation
+}
+
+type memCache struct{}
+
+func (memCache) Load(key string) string { return "" }
+
+type Service struct {
+	store Store
+}
+
+func (s *Service) Lookup(key string) (string, error) {
+	return s.store.Get(key)
+}
+
+func warm() string {
+	var c Cache = &memCache{}
+	return c.Load("key")
+}
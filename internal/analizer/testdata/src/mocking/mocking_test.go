@@ -0,0 +1,34 @@
+package mocking
+
+import (
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestLookup(t *testing.T) {
+	store := NewMockStore(&gomock.Controller{})
+	store.EXPECT().Get("key").Return("value", nil)
+	store.EXPECT().Put("key", "value").Return(nil)
+	store.Put("key", "value")
+
+	s := &Service{store: store}
+	if _, err := s.Lookup("key"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	notifier := &MockNotifier{}
+	notifier.On("Notify", "msg").Return(nil)
+	notifier.EXPECT().Notify("msg")
+	notifier.Notify("msg")
+}
+
+func TestNow(t *testing.T) {
+	clock := &ClockMock{NowFunc: func() int64 { return 0 }}
+	clock.Now()
+	if len(clock.NowCalls()) != 1 {
+		t.Fatal("Now() not called")
+	}
+}
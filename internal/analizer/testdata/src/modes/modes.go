@@ -0,0 +1,17 @@
+package modes
+
+// Not reported in unexported mode, may be used by downstream consumers
+type Store interface {
+	Get(key string) string
+	Put(key string)
+}
+
+// Reported in unexported mode
+type cache interface {
+	Load(key string) string // used
+	Evict(key string)       // want "method \"Evict\" of interface \"cache\" is declared but not used"
+}
+
+func use(c cache) string {
+	return c.Load("key")
+}
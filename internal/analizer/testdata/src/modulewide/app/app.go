@@ -0,0 +1,8 @@
+package app
+
+import "modulewide/store"
+
+// Lookup uses Store from another package
+func Lookup(s store.Store, key string) string {
+	return s.Get(key)
+}
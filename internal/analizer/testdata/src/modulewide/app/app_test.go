@@ -0,0 +1,15 @@
+package app
+
+import (
+	"testing"
+
+	"modulewide/store"
+)
+
+func fill(s store.Store) {
+	s.Put("key", "value")
+}
+
+func TestLookup(t *testing.T) {
+	_ = fill
+}
@@ -0,0 +1,8 @@
+package disk
+
+// Disk implements store.Store without importing the store package
+type Disk struct{}
+
+func (Disk) Get(key string) string { return "" }
+func (Disk) Put(key, value string) {}
+func (Disk) Delete(key string)     {}
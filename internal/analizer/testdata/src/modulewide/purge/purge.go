@@ -0,0 +1,10 @@
+package purge
+
+import "modulewide/disk"
+
+// Purge calls Delete on a concrete type implementing store.Store. Neither this
+// package nor its imports import the store package, so store.Store is not known
+// here and the call does not count as usage of store.Store.Delete.
+func Purge(d disk.Disk, key string) {
+	d.Delete(key)
+}
@@ -0,0 +1,7 @@
+package store
+
+type Store interface {
+	Get(key string) string
+	Put(key, value string)
+	Delete(key string)
+}
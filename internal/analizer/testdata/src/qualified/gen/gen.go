@@ -0,0 +1,6 @@
+package gen
+
+// Not reported, the package is ignored by "qualified/gen/..."
+type Service interface {
+	Call() error
+}
@@ -0,0 +1,18 @@
+package qualified
+
+// Ignored as a whole by "qualified.Plugin"
+type Plugin interface {
+	Init() error
+	Shutdown() error
+}
+
+// Only Put is ignored by "qualified.Store.Put"
+type Store interface {
+	Get(key string) string // used
+	Put(key string)
+	Delete(key string) // want "method \"Delete\" of interface \"Store\" is declared but not used"
+}
+
+func use(s Store) string {
+	return s.Get("key")
+}
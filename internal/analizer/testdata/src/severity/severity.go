@@ -0,0 +1,21 @@
+package severity
+
+// Reported with the default error level
+type Store interface {
+	Get(key string) string // used
+	Put(key string)        // want "method \"Put\" of interface \"Store\" is declared but not used"
+}
+
+// Reported as info by the interface name rule
+type EventHandler interface {
+	Handle() // want "method \"Handle\" of interface \"EventHandler\" is declared but not used"
+}
+
+// Not reported, unexported interfaces are off
+type cache interface {
+	Evict()
+}
+
+func Use(s Store) string {
+	return s.Get("key")
+}
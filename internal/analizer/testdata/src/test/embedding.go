@@ -0,0 +1,148 @@
+package test
+
+// ===============================
+// PROMOTED METHODS THROUGH EMBEDDED FIELDS
+// ===============================
+
+// Case 32: Interface embedded directly into a struct
+type Store interface {
+	Get(key string) (string, error) // used (promoted through storeService)
+	Put(key, value string) error    // want "method \"Put\" of interface \"Store\" is declared but not used"
+}
+
+type storeService struct {
+	Store
+}
+
+func (s *storeService) Lookup(key string) (string, error) {
+	return s.Get(key) // Store.Get via promotion
+}
+
+// Case 33: Multi-level embedding
+type Fetcher interface {
+	Fetch(url string) ([]byte, error) // used (promoted through two levels)
+	Abort()                           // want "method \"Abort\" of interface \"Fetcher\" is declared but not used"
+}
+
+type fetchLayer struct {
+	Fetcher
+}
+
+type fetchClient struct {
+	fetchLayer
+}
+
+func (c fetchClient) Download(url string) ([]byte, error) {
+	return c.Fetch(url) // Fetcher.Fetch via fetchClient.fetchLayer.Fetcher
+}
+
+// Case 34: Pointer embedding of a struct that embeds an interface
+type Notifier interface {
+	Notify(msg string) error // used (promoted through *notifyBase)
+	Mute()                   // want "method \"Mute\" of interface \"Notifier\" is declared but not used"
+}
+
+type notifyBase struct {
+	Notifier
+}
+
+type alertService struct {
+	*notifyBase
+}
+
+func (a *alertService) Alert(msg string) error {
+	return a.Notify(msg) // Notifier.Notify via *notifyBase
+}
+
+// Case 35: Embedded interface that itself embeds another interface
+type Closer interface {
+	CloseAll() error // used (promoted through Resource)
+}
+
+type Resource interface {
+	Closer
+	Name() string // want "method \"Name\" of interface \"Resource\" is declared but not used"
+}
+
+type resourceHolder struct {
+	Resource
+}
+
+func (r resourceHolder) Shutdown() error {
+	return r.CloseAll() // Closer.CloseAll via Resource
+}
+
+// Case 36: Embedded generic interface instantiation
+type KeyValue[T any] interface {
+	Load(key string) (T, bool) // used (promoted through embedded KeyValue[int])
+	Drop(key string)           // want "method \"Drop\" of interface \"KeyValue\" is declared but not used"
+}
+
+type counterStore struct {
+	KeyValue[int]
+}
+
+func (c *counterStore) Count(key string) int {
+	n, _ := c.Load(key) // KeyValue[int].Load via promotion
+	return n
+}
+
+// Case 37: Promoted method value through an embedded field of a named field
+type Pinger interface {
+	Ping() error // used (method value through nested embedding)
+	Pong() error // want "method \"Pong\" of interface \"Pinger\" is declared but not used"
+}
+
+type pingerHolder struct {
+	Pinger
+}
+
+type monitor struct {
+	holder pingerHolder
+}
+
+func (m *monitor) Probe() func() error {
+	return m.holder.Ping // Pinger.Ping via holder.Pinger
+}
+
+// Case 47: Method of an embedded struct implementing the interface, while the
+// outer struct does not since its Close field hides the promoted Close method
+type Sink interface {
+	Write(p []byte) (int, error) // used (promoted from fileSink)
+	Close() error                // want "method \"Close\" of interface \"Sink\" is declared but not used"
+}
+
+type fileSink struct{}
+
+func (fileSink) Write(p []byte) (int, error) { return len(p), nil }
+func (fileSink) Close() error                { return nil }
+
+type bufferedSink struct {
+	fileSink
+	Close bool
+}
+
+func (b bufferedSink) Flush(p []byte) error {
+	_, err := b.Write(p) // Sink.Write via fileSink
+	return err
+}
+
+// Case 48: Pointer-embedded struct whose pointer receiver methods implement the interface
+type Queue interface {
+	Push(item string) // used (promoted from *memQueue)
+	Pop() string      // want "method \"Pop\" of interface \"Queue\" is declared but not used"
+}
+
+type memQueue struct{ items []string }
+
+func (q *memQueue) Push(item string) { q.items = append(q.items, item) }
+func (q *memQueue) Pop() string      { return "" }
+
+type jobQueue struct {
+	*memQueue
+	Pop func() string
+}
+
+func (j jobQueue) Enqueue(job string) {
+	j.Push(job) // Queue.Push via *memQueue
+}
@@ -0,0 +1,154 @@
+package test
+
+// ===============================
+// METHOD EXPRESSIONS AND METHOD VALUES
+// ===============================
+
+// Case 38: Interface method expression
+type Decoder interface {
+	Decode(data []byte) error // used (method expression Decoder.Decode)
+	Reset()                   // want "method \"Reset\" of interface \"Decoder\" is declared but not used"
+}
+
+func decodeAll(d Decoder, chunks [][]byte) error {
+	decode := Decoder.Decode
+	for _, chunk := range chunks {
+		if err := decode(d, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Case 39: Parenthesized and generic interface method expressions
+type Encoder interface {
+	Encode(v any) ([]byte, error) // used (parenthesized method expression)
+}
+
+type Codec[T any] interface {
+	Marshal(v T) ([]byte, error) // used (method expression on Codec[string])
+	Unmarshal(data []byte) T     // want "method \"Unmarshal\" of interface \"Codec\" is declared but not used"
+}
+
+func encoders() (func(Encoder, any) ([]byte, error), func(Codec[string], string) ([]byte, error)) {
+	return (Encoder).Encode, Codec[string].Marshal
+}
+
+// Case 40: Method values passed as callbacks
+type Visitor interface {
+	Visit(node string) bool // used (passed as callback)
+	Leave(node string)      // want "method \"Leave\" of interface \"Visitor\" is declared but not used"
+}
+
+func walk(nodes []string, fn func(string) bool) {
+	for _, n := range nodes {
+		if !fn(n) {
+			return
+		}
+	}
+}
+
+func walkWith(v Visitor, nodes []string) {
+	walk(nodes, v.Visit)
+}
+
+// Case 41: Method values stored in maps and slices of funcs
+type Command interface {
+	Run() error       // used (stored in map)
+	Undo() error      // used (stored in slice)
+	Describe() string // want "method \"Describe\" of interface \"Command\" is declared but not used"
+}
+
+func commandTable(c Command) (map[string]func() error, []func() error) {
+	table := map[string]func() error{
+		"run": c.Run,
+	}
+	steps := []func() error{c.Undo}
+	return table, steps
+}
+
+// Case 42: Method values and expressions stored in struct fields
+type Validator interface {
+	Validate(input string) error // used (stored in struct field)
+	Strict() bool                // used (method expression stored in struct field)
+	Explain() string             // want "method \"Explain\" of interface \"Validator\" is declared but not used"
+}
+
+type validationStep struct {
+	check  func(string) error
+	strict func(Validator) bool
+}
+
+func newValidationStep(v Validator) validationStep {
+	return validationStep{
+		check:  v.Validate,
+		strict: Validator.Strict,
+	}
+}
+
+// Case 43: Method values returned from functions
+type Clock interface {
+	Now() int64   // used (returned as method value)
+	Since() int64 // used (returned as method expression)
+	Zone() string // want "method \"Zone\" of interface \"Clock\" is declared but not used"
+}
+
+func nowFunc(c Clock) func() int64 {
+	return c.Now
+}
+
+func sinceFunc() func(Clock) int64 {
+	return Clock.Since
+}
+
+// Case 44: Method expressions on a concrete type count like method values on it
+type Shape interface {
+	Area() float64      // used (method expression Square.Area)
+	Perimeter() float64 // used (method expression (*Square).Perimeter)
+	Name() string       // want "method \"Name\" of interface \"Shape\" is declared but not used"
+}
+
+type Square struct{ side float64 }
+
+func (s Square) Area() float64      { return s.side * s.side }
+func (s Square) Perimeter() float64 { return 4 * s.side }
+func (s Square) Name() string       { return "square" }
+
+func squareMetrics() (func(Square) float64, func(*Square) float64) {
+	return Square.Area, (*Square).Perimeter
+}
+
+// Case 45: Method expressions and values on a type not implementing the interface
+type Solid interface {
+	Volume() float64  // want "method \"Volume\" of interface \"Solid\" is declared but not used"
+	Surface() float64 // want "method \"Surface\" of interface \"Solid\" is declared but not used"
+}
+
+type Cube struct{ side float64 }
+
+func (c Cube) Volume() float64 { return c.side * c.side * c.side }
+
+func cubeVolume(c Cube) (func(Cube) float64, func() float64) {
+	return Cube.Volume, c.Volume
+}
+
+// Case 46: Method expressions promoted through embedded fields, by value and by pointer
+type Sizer interface {
+	Size() int  // used (method expression Ruler.Size promoted from Length)
+	Grow(n int) // used (method expression Tape.Grow promoted from *Length)
+	Trim()      // want "method \"Trim\" of interface \"Sizer\" is declared but not used"
+}
+
+type Length struct{ n int }
+
+func (l Length) Size() int  { return l.n }
+func (l Length) Grow(n int) {}
+func (l Length) Trim()      {}
+
+type Ruler struct{ Length }
+
+type Tape struct{ *Length }
+
+func lengthMethods() (func(Ruler) int, func(Tape, int)) {
+	return Ruler.Size, Tape.Grow
+}
@@ -0,0 +1,19 @@
+package testusage
+
+// Case 1: Method used from regular code and tests
+// Case 2: Method used only from tests
+// Case 3: Method not used at all
+type Store interface {
+	Get(key string) string
+	Put(key, value string)
+	Delete(key string)
+}
+
+func Lookup(s Store, key string) string {
+	return s.Get(key)
+}
+
+// Case 5: a test harness holding a mock is not a mock, calls on it are test usage
+type Runner interface {
+	Run() error
+}
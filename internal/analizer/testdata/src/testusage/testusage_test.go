@@ -0,0 +1,35 @@
+package testusage
+
+import "testing"
+
+// Case 4: Test-local interfaces are not reported, test files are ignored by default
+type fixture interface {
+	Setup()
+}
+
+type memStore map[string]string
+
+func (m memStore) Get(key string) string { return m[key] }
+func (m memStore) Put(key, value string) { m[key] = value }
+func (m memStore) Delete(key string)     { delete(m, key) }
+
+func TestLookup(t *testing.T) {
+	var s Store = memStore{}
+	s.Put("key", "value")
+	if got := Lookup(s, "key"); got != s.Get("key") {
+		t.Errorf("Lookup() = %q", got)
+	}
+}
+
+type harness struct {
+	dep *MockDep
+}
+
+func (h *harness) Run() error { return h.dep.Run() }
+
+func TestHarness(t *testing.T) {
+	h := &harness{dep: &MockDep{}}
+	if err := h.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
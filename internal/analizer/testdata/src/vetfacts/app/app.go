@@ -0,0 +1,14 @@
+package app
+
+import "vetfacts/sync"
+
+type Store interface {
+	Get() string  // want Get:"called"
+	Version() int // want `method "Version" of interface "Store" is declared but not used`
+	Put(string)   // want Put:"called"
+}
+
+func Run(s Store) string {
+	s.Put("value")
+	return sync.Sync(s)
+}
@@ -0,0 +1,12 @@
+package sync
+
+// Getter is implemented by stores passed to Sync.
+type Getter interface {
+	Get() string  // want Get:"called"
+	Version() int // want `method "Version" of interface "Getter" is declared but not used`
+}
+
+// Sync reads the value of the store without checking its version.
+func Sync(g Getter) string {
+	return g.Get()
+}
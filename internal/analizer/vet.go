@@ -0,0 +1,146 @@
+package analizer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+// calledFact marks an interface method used in the package declaring its interface.
+// go vet analyzes dependencies before the packages importing them, so a package
+// passing its own interface values to a dependency knows which methods the
+// dependency calls through its parameter types.
+type calledFact struct{}
+
+func (*calledFact) AFact() {}
+
+func (*calledFact) String() string { return "called" }
+
+// newVetAnalyzer returns the analyzer run by go vet -vettool, like newAnalyzer does.
+// Unlike the standalone driver, go vet runs it on dependencies too, exporting
+// calledFact for them.
+func newVetAnalyzer(s *settings) *analysis.Analyzer {
+	vetAnalyzer := newAnalyzer(s)
+	vetAnalyzer.FactTypes = []analysis.Fact{new(calledFact)}
+	return vetAnalyzer
+}
+
+// isVetInvocation checks if the arguments follow the go vet -vettool protocol:
+// a query of the tool version or flags, or the path of a vet.cfg file
+func isVetInvocation(args []string) bool {
+	for _, arg := range args {
+		if arg == "-flags" || arg == "-V" || strings.HasPrefix(arg, "-V=") {
+			return true
+		}
+	}
+	return len(args) > 0 && strings.HasSuffix(args[len(args)-1], ".cfg")
+}
+
+// runVet serves go vet -vettool and exits. go vet runs the tool in the directory
+// of each package, so the configuration is loaded from the root of its module.
+func runVet(args []string, stdout, stderr io.Writer) {
+	var cfg *config.Config
+	dir, err := os.Getwd()
+	if err == nil {
+		cfg, err = config.LoadConfigFrom(moduleRoot(dir))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		os.Exit(exitFailure)
+	}
+
+	for _, arg := range args {
+		if arg == "-V=full" {
+			if err := printVetVersion(stdout, cfg); err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+				os.Exit(exitFailure)
+			}
+			os.Exit(exitOK)
+		}
+	}
+	unitchecker.Main(newVetAnalyzer(&settings{cfg: cfg}))
+}
+
+// printVetVersion prints the tool ID go vet uses in its cache keys. Unlike the ID
+// printed by unitchecker, it covers the configuration too, so that editing the
+// configuration file invalidates cached results.
+func printVetVersion(w io.Writer, cfg *config.Config) error {
+	h := sha256.New()
+	if err := hashExecutable(h); err != nil {
+		return err
+	}
+	if err := json.NewEncoder(h).Encode(cfg); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s version devel comments-go-here buildID=%x\n", a.Name, h.Sum(nil))
+	return err
+}
+
+// exportCalledFacts exports calledFact for the used methods declared in the package
+func exportCalledFacts(pass *analysis.Pass, ifaceMethods map[*types.Func]methodInfo, used, testUsed map[*types.Func]bool) {
+	for method, info := range ifaceMethods {
+		if !info.foreign && (used[method] || testUsed[method]) {
+			pass.ExportObjectFact(method, new(calledFact))
+		}
+	}
+}
+
+// markPassedMethods marks methods of interface values passed as arguments to
+// interface parameters declared in other packages, when calledFact shows that
+// the method of the parameter type is used there
+func (ma *methodAnalyzer) markPassedMethods(call *ast.CallExpr) {
+	tv, ok := ma.pass.TypesInfo.Types[call.Fun]
+	if !ok || tv.IsType() || tv.IsBuiltin() {
+		return
+	}
+	sig, ok := tv.Type.Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	params := sig.Params()
+	for i, arg := range call.Args {
+		var param types.Type
+		switch {
+		case sig.Variadic() && i >= params.Len()-1:
+			param = params.At(params.Len() - 1).Type()
+			if call.Ellipsis == token.NoPos {
+				param = param.(*types.Slice).Elem()
+			}
+		case i < params.Len():
+			param = params.At(i).Type()
+		default:
+			continue
+		}
+
+		paramIface, ok := param.Underlying().(*types.Interface)
+		if !ok || !types.IsInterface(ma.pass.TypesInfo.TypeOf(arg)) {
+			continue
+		}
+		for j := 0; j < paramIface.NumMethods(); j++ {
+			called := paramIface.Method(j)
+			if called.Pkg() == ma.pass.Pkg || !ma.pass.ImportObjectFact(called, new(calledFact)) {
+				continue
+			}
+			obj, _, _ := types.LookupFieldOrMethod(ma.pass.TypesInfo.TypeOf(arg), false, ma.pass.Pkg, called.Name())
+			if method, ok := obj.(*types.Func); ok {
+				if _, ok := ma.ifaceMethods[method]; ok {
+					ma.markUsed(method, rulePassed)
+					if verbose {
+						fmt.Fprintf(os.Stderr, "[DEBUG] Marking %s as used (passed to %s)\n",
+							ma.ifaceMethods[method].qualifiedName(), types.TypeString(param, nil))
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package analizer
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestVetAnalyzerFacts(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, newVetAnalyzer(nil), "vetfacts/app")
+}
+
+func TestIsVetInvocation(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"-V=full"}, true},
+		{[]string{"-flags"}, true},
+		{[]string{"-json", "/tmp/go-build1/b001/vet.cfg"}, true},
+		{[]string{"./..."}, false},
+		{[]string{"-module", "./cmd/...", "./internal/..."}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isVetInvocation(tt.args); got != tt.want {
+			t.Errorf("isVetInvocation(%q) = %t, want %t", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package analizer
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/packages"
+)
+
+// watchDebounce is how long to wait for more file events before re-analyzing
+const watchDebounce = 100 * time.Millisecond
+
+// watcher keeps the results of all packages in memory and re-analyzes only
+// packages affected by file changes, printing findings added or removed
+type watcher struct {
+	settings *settings
+	dir      string // directory resolving the package patterns, the current directory when empty
+	w        io.Writer
+	tests    bool
+	tags     string
+	module   bool
+	cache    *analysisCache
+	results  map[string]*packageResult // by package ID
+	apis     map[string]string         // exported API of each package by ID, see packageAPI
+	findings map[string]finding        // current findings by findingKey
+}
+
+// newWatcher creates a watcher starting from the results of the initial run
+func newWatcher(s *settings, dir string, w io.Writer, results []*packageResult, tests bool, tags string, module bool, cache *analysisCache) *watcher {
+	wt := &watcher{
+		settings: s,
+		dir:      dir,
+		w:        w,
+		tests:    tests,
+		tags:     tags,
+		module:   module,
+		cache:    cache,
+		results:  make(map[string]*packageResult, len(results)),
+		apis:     make(map[string]string, len(results)),
+	}
+	for _, result := range results {
+		wt.results[result.pkg.ID] = result
+		wt.apis[result.pkg.ID] = packageAPI(result.pkg.Types)
+	}
+	wt.findings = wt.currentFindings()
+	return wt
+}
+
+// findingKey identifies a finding across runs, independently of line shifts
+func findingKey(f finding) string {
+	return f.Posn.Filename + "\x00" + string(f.Severity) + "\x00" + f.Message
+}
+
+// sortedResults returns the results of all packages sorted by package ID
+func (wt *watcher) sortedResults() []*packageResult {
+	ids := make([]string, 0, len(wt.results))
+	for id := range wt.results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	results := make([]*packageResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, wt.results[id])
+	}
+	return results
+}
+
+// currentFindings computes findings from the results of all packages
+func (wt *watcher) currentFindings() map[string]finding {
+	results := wt.sortedResults()
+	var findings []finding
+	if wt.module {
+		findings, _ = wt.settings.moduleFindings(io.Discard, results)
+	} else {
+		findings, _ = packageFindings(io.Discard, results)
+	}
+	byKey := make(map[string]finding, len(findings))
+	for _, f := range findings {
+		byKey[findingKey(f)] = f
+	}
+	return byKey
+}
+
+// run watches package directories until the context is done
+func (wt *watcher) run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	for _, dir := range wt.dirs() {
+		if err := fsw.Add(dir); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(wt.w, "Watching %d packages for changes...\n", len(wt.results))
+
+	changed := make(map[string]bool)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(wt.w, "%s: %v\n", a.Name, err)
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+				// New directory tree, possibly with new packages
+				if event.Has(fsnotify.Create) {
+					for _, dir := range watchTree(fsw, event.Name) {
+						changed[dir] = true
+					}
+					debounce = time.After(watchDebounce)
+				}
+				continue
+			}
+			if !strings.HasSuffix(event.Name, ".go") || event.Op == fsnotify.Chmod {
+				continue
+			}
+			changed[filepath.Dir(event.Name)] = true
+			debounce = time.After(watchDebounce)
+		case <-debounce:
+			dirs := make([]string, 0, len(changed))
+			for dir := range changed {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			clear(changed)
+			debounce = nil
+			wt.update(dirs)
+		}
+	}
+}
+
+// dirs returns the directories of the watched packages
+func (wt *watcher) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, result := range wt.results {
+		if dir := result.pkg.Dir; dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// watchTree adds watches for the directory and all directories below it, returning
+// the directories added. Directories ignored by go list are skipped.
+func watchTree(fsw *fsnotify.Watcher, root string) []string {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata") {
+			return filepath.SkipDir
+		}
+		if err := fsw.Add(path); err == nil {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// update re-analyzes packages in the changed directories and the packages importing them
+func (wt *watcher) update(dirs []string) {
+	start := time.Now()
+	change, ok := wt.reanalyze(dirs)
+	if !ok {
+		return
+	}
+	wt.apply(change)
+	if verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Re-analyzed %s in %v\n", strings.Join(change.patterns, " "), time.Since(start))
+	}
+}
+
+// watchChange is the outcome of re-analyzing changed directories
+type watchChange struct {
+	patterns []string         // patterns of the reloaded packages
+	stale    map[string]bool  // IDs of packages whose results are replaced
+	results  []*packageResult // results of the reloaded packages
+}
+
+// reanalyze loads and analyzes the packages affected by changes in dirs, leaving
+// the results of the watcher untouched. It returns false if loading fails, so
+// that the previous results are kept until the code compiles again.
+//
+// Packages importing the changed packages are reloaded only when the exported
+// API of a changed package differs, since edits inside function bodies cannot
+// change how importers use interface methods.
+func (wt *watcher) reanalyze(dirs []string) (*watchChange, bool) {
+	change, ok := wt.reload(wt.affected(dirs, false))
+	if ok && wt.apiChanged(change) {
+		change, ok = wt.reload(wt.affected(dirs, true))
+	}
+	return change, ok
+}
+
+// apiChanged checks if the exported API of the reloaded packages differs from
+// the API of the packages they replace
+func (wt *watcher) apiChanged(change *watchChange) bool {
+	if len(change.results) != len(change.stale) {
+		return true
+	}
+	for _, result := range change.results {
+		api, ok := wt.apis[result.pkg.ID]
+		if !ok || !change.stale[result.pkg.ID] || api != packageAPI(result.pkg.Types) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageAPI describes the exported declarations of the package, including
+// the methods of exported types
+func packageAPI(pkg *types.Package) string {
+	if pkg == nil {
+		return ""
+	}
+	var b strings.Builder
+	qualifier := types.RelativeTo(pkg)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		b.WriteString(types.ObjectString(obj, qualifier))
+		b.WriteByte('\n')
+		if named, ok := obj.Type().(*types.Named); ok && !obj.(*types.TypeName).IsAlias() {
+			for i := 0; i < named.NumMethods(); i++ {
+				if m := named.Method(i); m.Exported() {
+					b.WriteString(types.ObjectString(m, qualifier))
+					b.WriteByte('\n')
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// reload loads and analyzes the packages matching the patterns, which replace
+// the packages with the stale IDs
+func (wt *watcher) reload(patterns []string, stale map[string]bool) (*watchChange, bool) {
+	change := &watchChange{patterns: patterns, stale: stale}
+	if len(patterns) == 0 {
+		return change, true
+	}
+
+	var err error
+	if change.results, err = wt.settings.loadAndAnalyze(wt.dir, patterns, wt.tests, wt.tags, wt.cache); err != nil {
+		fmt.Fprintf(wt.w, "%s: %v\n", a.Name, err)
+		return nil, false
+	}
+	return change, true
+}
+
+// apply replaces the results of the changed packages and prints findings added or removed
+func (wt *watcher) apply(change *watchChange) {
+	for id := range change.stale {
+		delete(wt.results, id)
+		delete(wt.apis, id)
+	}
+	for _, result := range change.results {
+		if result.err != nil {
+			reportResultError(wt.w, result)
+		}
+		wt.results[result.pkg.ID] = result
+		wt.apis[result.pkg.ID] = packageAPI(result.pkg.Types)
+	}
+
+	findings := wt.currentFindings()
+	wt.printChanges(findings)
+	wt.findings = findings
+}
+
+// affected returns patterns of packages to reload for changes in dirs, with
+// the packages importing them if importers is set, and the IDs of packages
+// whose results are replaced
+func (wt *watcher) affected(dirs []string, importers bool) ([]string, map[string]bool) {
+	changed := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		changed[dir] = true
+	}
+
+	// Import paths of the packages in changed directories, then of their importers
+	importedBy := make(map[string][]*packages.Package)
+	affectedPaths := make(map[string]bool)
+	var queue []string
+	for _, result := range wt.results {
+		pkg := result.pkg
+		for path := range pkg.Imports {
+			importedBy[path] = append(importedBy[path], pkg)
+		}
+		if changed[pkg.Dir] && !affectedPaths[basePkgPath(pkg)] {
+			affectedPaths[basePkgPath(pkg)] = true
+			queue = append(queue, pkg.PkgPath)
+			delete(changed, pkg.Dir)
+		}
+	}
+	for importers && len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, importer := range importedBy[path] {
+			if base := basePkgPath(importer); !affectedPaths[base] {
+				affectedPaths[base] = true
+				queue = append(queue, importer.PkgPath)
+			}
+		}
+	}
+
+	stale := make(map[string]bool)
+	var patterns []string
+	for id, result := range wt.results {
+		if affectedPaths[basePkgPath(result.pkg)] {
+			stale[id] = true
+		}
+	}
+	for path := range affectedPaths {
+		if pkgDir := wt.dirOf(path); pkgDir == "" || hasGoFiles(pkgDir) {
+			patterns = append(patterns, path)
+		}
+	}
+	// Directories without known packages, like a new package
+	for dir := range changed {
+		if hasGoFiles(dir) {
+			patterns = append(patterns, dir)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns, stale
+}
+
+// dirOf returns the directory of the package with the import path
+func (wt *watcher) dirOf(path string) string {
+	for _, result := range wt.results {
+		if result.pkg.PkgPath == path {
+			return result.pkg.Dir
+		}
+	}
+	return ""
+}
+
+// basePkgPath returns the import path of the package under test for test variants
+// and generated test main packages
+func basePkgPath(pkg *packages.Package) string {
+	if pkg.ForTest != "" {
+		return pkg.ForTest
+	}
+	return strings.TrimSuffix(pkg.PkgPath, ".test")
+}
+
+// hasGoFiles checks if the directory contains Go files
+func hasGoFiles(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	return len(matches) > 0
+}
+
+// printChanges prints findings missing from the previous run with "+"
+// and findings gone since the previous run with "-"
+func (wt *watcher) printChanges(findings map[string]finding) {
+	var added, removed []finding
+	for key, f := range findings {
+		if _, ok := wt.findings[key]; !ok {
+			added = append(added, f)
+		}
+	}
+	for key, f := range wt.findings {
+		if _, ok := findings[key]; !ok {
+			removed = append(removed, f)
+		}
+	}
+	sortFindings(removed)
+	sortFindings(added)
+	for _, f := range removed {
+		fmt.Fprintf(wt.w, "- %s\n", formatFinding(f))
+	}
+	for _, f := range added {
+		fmt.Fprintf(wt.w, "+ %s\n", formatFinding(f))
+	}
+}
@@ -0,0 +1,132 @@
+package analizer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatcherUpdate(t *testing.T) {
+	root := chdirModule(t, map[string]string{
+		"go.mod":         "module example.com/w\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n}\n",
+		"app/app.go":     "package app\n\nimport \"example.com/w/store\"\n\nfunc Use(s store.Store) string { return s.Get() }\n",
+	})
+
+	pkgs, err := loadPackages([]string{"./..."}, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := defaultSettings(t)
+	s.moduleScope = newModuleScope(pkgs)
+	results, err := s.analyzePackages(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	wt := newWatcher(s, "", &buf, results, false, "", true, nil)
+	storeGo := filepath.Join(root, "store", "store.go")
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "method added",
+			content: "package store\n\ntype Store interface {\n\tGet() string\n\tPut()\n\tDrop()\n}\n",
+			want:    []string{"+ " + storeGo + `:6:2: method "Drop" of interface "Store" is declared but not used`},
+		},
+		{
+			name:    "line shift only",
+			content: "package store\n\n// Store stores.\ntype Store interface {\n\tGet() string\n\tPut()\n\tDrop()\n}\n",
+		},
+		{
+			name:    "method removed",
+			content: "package store\n\n// Store stores.\ntype Store interface {\n\tGet() string\n\tDrop()\n}\n",
+			want:    []string{"- " + storeGo + `:6:2: method "Put" of interface "Store" is declared but not used`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			writeFiles(t, root, map[string]string{"store/store.go": tt.content})
+			wt.update([]string{filepath.Join(root, "store")})
+			if got, want := strings.TrimSpace(buf.String()), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("update() output:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	// Importers are re-analyzed with the changed package, not the other way round
+	patterns, stale := wt.affected([]string{filepath.Join(root, "store")}, true)
+	if got, want := strings.Join(patterns, " "), "example.com/w/app example.com/w/store"; got != want {
+		t.Errorf("affected(store) patterns = %q, want %q", got, want)
+	}
+	if len(stale) != 2 {
+		t.Errorf("affected(store) replaces %d packages, want 2", len(stale))
+	}
+	patterns, _ = wt.affected([]string{filepath.Join(root, "app")}, true)
+	if got, want := strings.Join(patterns, " "), "example.com/w/app"; got != want {
+		t.Errorf("affected(app) patterns = %q, want %q", got, want)
+	}
+
+	// Importers are reloaded only when the exported API changes
+	reloads := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "unexported change",
+			content: "package store\n\n// Store stores.\ntype Store interface {\n\tGet() string\n\tDrop()\n}\n\nfunc helper() {}\n",
+			want:    "example.com/w/store",
+		},
+		{
+			name:    "exported change",
+			content: "package store\n\n// Store stores.\ntype Store interface {\n\tGet() string\n\tDrop()\n}\n\nfunc Helper() {}\n",
+			want:    "example.com/w/app example.com/w/store",
+		},
+	}
+	for _, tt := range reloads {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFiles(t, root, map[string]string{"store/store.go": tt.content})
+			change, ok := wt.reanalyze([]string{filepath.Join(root, "store")})
+			if !ok {
+				t.Fatal("reanalyze() failed")
+			}
+			wt.apply(change)
+			if got := strings.Join(change.patterns, " "); got != tt.want {
+				t.Errorf("reanalyze() reloaded %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchTree(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a/b/c", "a/testdata/d", "a/.git"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsw.Close()
+
+	got := watchTree(fsw, filepath.Join(root, "a"))
+	want := []string{filepath.Join(root, "a"), filepath.Join(root, "a", "b"), filepath.Join(root, "a", "b", "c")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("watchTree() = %q, want %q", got, want)
+	}
+	if watched := fsw.WatchList(); len(watched) != len(want) {
+		t.Errorf("watched directories = %q, want %q", watched, want)
+	}
+}
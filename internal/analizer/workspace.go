@@ -0,0 +1,112 @@
+package analizer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// errNoWorkspace is returned when the current directory is not in a go.work workspace
+var errNoWorkspace = errors.New("no go.work workspace found")
+
+// workspaceModules returns the go.work file of the workspace containing dir
+// and the directories of the modules it uses
+func workspaceModules(dir string) (string, []string, error) {
+	cmd := exec.Command("go", "env", "GOWORK")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("go env GOWORK: %w", err)
+	}
+	gowork := strings.TrimSpace(string(out))
+	if gowork == "" || gowork == "off" {
+		return "", nil, errNoWorkspace
+	}
+
+	data, err := os.ReadFile(gowork)
+	if err != nil {
+		return "", nil, err
+	}
+	wf, err := modfile.ParseWork(gowork, data, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	root := filepath.Dir(gowork)
+	dirs := make([]string, 0, len(wf.Use))
+	for _, use := range wf.Use {
+		modDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(modDir) {
+			modDir = filepath.Join(root, modDir)
+		}
+		dirs = append(dirs, modDir)
+	}
+	return gowork, dirs, nil
+}
+
+// moduleSummary counts packages, declared interface methods and findings of one module
+type moduleSummary struct {
+	path     string
+	packages map[string]bool
+	methods  map[string]bool
+	findings int
+}
+
+// summarizeModules groups the analyzed packages and findings by module, sorted by module path
+func summarizeModules(results []*packageResult, findings []finding) []*moduleSummary {
+	byPath := make(map[string]*moduleSummary)
+	byPkgID := make(map[string]*moduleSummary)
+
+	for _, result := range results {
+		pkg := result.pkg
+		if pkg.Module == nil || strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue // generated test main packages
+		}
+		summary, ok := byPath[pkg.Module.Path]
+		if !ok {
+			summary = &moduleSummary{
+				path:     pkg.Module.Path,
+				packages: make(map[string]bool),
+				methods:  make(map[string]bool),
+			}
+			byPath[pkg.Module.Path] = summary
+		}
+		byPkgID[pkg.ID] = summary
+		summary.packages[strings.TrimSuffix(pkg.PkgPath, "_test")] = true
+
+		if result.usage != nil {
+			for _, m := range result.usage.Declared {
+				summary.methods[m.qualifiedName()] = true
+			}
+		}
+	}
+	for _, f := range findings {
+		if summary, ok := byPkgID[f.PkgID]; ok {
+			summary.findings++
+		}
+	}
+
+	summaries := make([]*moduleSummary, 0, len(byPath))
+	for _, summary := range byPath {
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].path < summaries[j].path
+	})
+	return summaries
+}
+
+// printModuleSummaries prints one line per module
+func printModuleSummaries(w io.Writer, summaries []*moduleSummary) {
+	for _, s := range summaries {
+		fmt.Fprintf(w, "module %s: %d packages, %d interface methods, %d findings\n",
+			s.path, len(s.packages), len(s.methods), s.findings)
+	}
+}
@@ -0,0 +1,64 @@
+package analizer
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeWorkspace creates a go.work workspace where module b uses an interface of module a
+func writeWorkspace(t *testing.T) string {
+	t.Helper()
+	return writeModule(t, map[string]string{
+		"go.work":  "go 1.24\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.24\n",
+		"a/a.go":   "package a\n\ntype Store interface {\n\tGet() string\n\tPut()\n}\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.24\n",
+		"b/b.go":   "package b\n\nimport \"example.com/a\"\n\nfunc Use(s a.Store) string { return s.Get() }\n",
+	})
+}
+
+func TestWorkspaceModules(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := writeWorkspace(t)
+
+	gowork, dirs, err := workspaceModules(filepath.Join(root, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "go.work"); gowork != want {
+		t.Errorf("workspaceModules() go.work = %q, want %q", gowork, want)
+	}
+	want := []string{filepath.Join(root, "a"), filepath.Join(root, "b")}
+	if strings.Join(dirs, ",") != strings.Join(want, ",") {
+		t.Errorf("workspaceModules() dirs = %v, want %v", dirs, want)
+	}
+
+	if _, _, err := workspaceModules(t.TempDir()); err != errNoWorkspace {
+		t.Errorf("workspaceModules() outside a workspace error = %v, want %v", err, errNoWorkspace)
+	}
+}
+
+func TestDriverWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := writeWorkspace(t)
+	t.Chdir(root)
+
+	var stdout, stderr bytes.Buffer
+	code := runDriver([]string{"-workspace"}, &stdout, &stderr)
+
+	want := strings.Join([]string{
+		filepath.Join(root, "a", "a.go") + `:5:2: method "Put" of interface "Store" is declared but not used`,
+		"module example.com/a: 1 packages, 2 interface methods, 1 findings",
+		"module example.com/b: 1 packages, 0 interface methods, 0 findings",
+	}, "\n")
+	if got := strings.TrimSpace(stderr.String()); got != want {
+		t.Errorf("runDriver() output:\n%s\nwant:\n%s", got, want)
+	}
+	if code != exitFindings {
+		t.Errorf("runDriver() = %d, want %d", code, exitFindings)
+	}
+}
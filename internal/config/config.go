@@ -1,17 +1,74 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"gopkg.in/yaml.v3"
 )
 
+// Severity is the level of a reported finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // reported, fails the run
+	SeverityWarning Severity = "warning" // reported, does not fail the run
+	SeverityInfo    Severity = "info"    // reported, does not fail the run
+	SeverityOff     Severity = "off"     // not reported
+)
+
+// Mode restricts which interfaces are reported
+type Mode string
+
+const (
+	ModeAll        Mode = "all"        // all interfaces
+	ModeExported   Mode = "exported"   // exported interfaces only
+	ModeUnexported Mode = "unexported" // unexported interfaces only, always safe to prune
+	ModeInternal   Mode = "internal"   // interfaces in internal/ packages only
+)
+
+// SeverityRule assigns a severity to findings matching all of its selectors.
+// Empty selectors match everything.
+type SeverityRule struct {
+	// Import path patterns of packages, "..." matches any string (e.g. "github.com/acme/svc/internal/...")
+	Packages []string `yaml:"packages"`
+	// Glob patterns for interface names (e.g. "*Handler")
+	Interfaces []string `yaml:"interfaces"`
+	// Match only exported (true) or only unexported (false) interfaces
+	Exported *bool `yaml:"exported"`
+	// Severity of matching findings
+	Level Severity `yaml:"level"`
+}
+
 // Config contains linter settings
 type Config struct {
-	// Patterns for ignoring files and directories
+	// Patterns for ignoring files and directories, interfaces declared there are not reported
 	Ignore []string `yaml:"ignore"`
+	// Report interfaces declared in generated files ("// Code generated ... DO NOT EDIT.")
+	IncludeGenerated bool `yaml:"include-generated"`
+	// Patterns for files whose calls do not count as usage
+	IgnoreUsage []string `yaml:"ignore-usage"`
+	// Patterns for test files, calls there count as usage but are reported as test-only ("**/*_test.go" when empty)
+	TestFiles []string `yaml:"test-files"`
+	// Severity of methods used only from test files (warning when empty)
+	TestOnlySeverity Severity `yaml:"test-only-severity"`
+	// Import path patterns of packages to ignore, "..." matches any string (e.g. "github.com/acme/svc/internal/...")
+	IgnorePackages []string `yaml:"ignore-packages"`
+	// Qualified names of interfaces ("pkg.Iface") or methods ("pkg.Iface.Method") to ignore,
+	// "pkg#Iface.Method" separates a package whose last element has dots explicitly
+	IgnoreInterfaces []string `yaml:"ignore-interfaces"`
+	// Qualified names of interfaces or methods implemented by external consumers, never reported
+	Contracts []string `yaml:"contracts"`
+	// Which interfaces are reported (all when empty)
+	Mode Mode `yaml:"mode"`
+	// Severity of findings not matched by any rule (error when empty)
+	Severity Severity `yaml:"severity"`
+	// Rules for per-package and per-interface severity, first match wins
+	SeverityRules []SeverityRule `yaml:"severity-rules"`
 }
 
 // defaultConfig returns the default configuration
@@ -27,8 +84,8 @@ func defaultConfig() *Config {
 	}
 }
 
-// findConfigFile searches for a configuration file in standard locations
-func findConfigFile() string {
+// findConfigFile searches for a configuration file in standard locations under dir
+func findConfigFile(dir string) string {
 	candidates := []string{
 		".unused-interface-methods.yml",
 		"unused-interface-methods.yml",
@@ -39,8 +96,9 @@ func findConfigFile() string {
 	}
 
 	for _, candidate := range candidates {
-		if _, err := os.Stat(candidate); err == nil {
-			return candidate
+		path := filepath.Join(dir, candidate)
+		if _, err := os.Stat(path); err == nil {
+			return path
 		}
 	}
 
@@ -59,23 +117,285 @@ func (c *Config) matchPattern(pattern, filePath string) bool {
 
 // ShouldIgnore checks if a file or directory should be ignored
 func (c *Config) ShouldIgnore(filePath string) bool {
-	// Normalize path - convert backslashes to forward slashes for consistent matching
-	filePath = filepath.ToSlash(filepath.Clean(filePath))
+	return c.matchAnyPattern(c.Ignore, filePath)
+}
+
+// ShouldIgnoreUsage checks if calls in a file should not count as usage
+func (c *Config) ShouldIgnoreUsage(filePath string) bool {
+	return c.matchAnyPattern(c.IgnoreUsage, filePath)
+}
+
+// IsTestFile checks if a file is a test file whose calls are reported as test-only usage
+func (c *Config) IsTestFile(filePath string) bool {
+	patterns := c.TestFiles
+	if len(patterns) == 0 {
+		patterns = []string{"**/*_test.go"}
+	}
+	return c.matchAnyPattern(patterns, filePath)
+}
 
-	for _, pattern := range c.Ignore {
+// matchAnyPattern checks if a file matches any of the patterns
+func (c *Config) matchAnyPattern(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
 		if c.matchPattern(pattern, filePath) {
 			return true
 		}
 	}
+	return false
+}
+
+// ShouldIgnorePackage checks if a package should be ignored by its import path
+func (c *Config) ShouldIgnorePackage(pkgPath string) bool {
+	for _, pattern := range c.IgnorePackages {
+		if MatchImportPath(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
 
+// ShouldIgnoreInterface checks if all methods of an interface should be ignored by its qualified name
+func (c *Config) ShouldIgnoreInterface(pkgPath, ifaceName string) bool {
+	return c.ShouldIgnoreMethod(pkgPath, ifaceName, "")
+}
+
+// ShouldIgnoreMethod checks if an interface method should be ignored by its qualified name.
+// An empty method name matches only patterns naming the whole interface.
+func (c *Config) ShouldIgnoreMethod(pkgPath, ifaceName, methodName string) bool {
+	return matchQualifiedName(c.IgnoreInterfaces, pkgPath, ifaceName, methodName)
+}
+
+// IsContract checks if an interface method is declared as a public contract by its qualified name.
+// An empty method name matches only patterns naming the whole interface.
+func (c *Config) IsContract(pkgPath, ifaceName, methodName string) bool {
+	return matchQualifiedName(c.Contracts, pkgPath, ifaceName, methodName)
+}
+
+// matchQualifiedName checks if an interface method matches any of the qualified name patterns
+func matchQualifiedName(patterns []string, pkgPath, ifaceName, methodName string) bool {
+	for _, pattern := range patterns {
+		for _, q := range splitQualifiedName(pattern) {
+			if !MatchImportPath(q.pkgPath, pkgPath) || !MatchName(q.ifaceName, ifaceName) {
+				continue
+			}
+			if q.methodName == "" || (methodName != "" && MatchName(q.methodName, methodName)) {
+				return true
+			}
+		}
+	}
 	return false
 }
 
+// qualifiedName is one reading of a qualified name pattern
+type qualifiedName struct {
+	pkgPath, ifaceName, methodName string
+}
+
+// splitQualifiedName returns the readings of "import/path.Iface.Method", the method
+// being optional. The last element of an import path may contain dots, as in
+// "gopkg.in/yaml.v3.Node" or "example.com.Iface", so every dot after the last slash
+// that is not part of a "..." wildcard may separate the package from the names.
+// "import/path#Iface.Method" separates the package explicitly and has one reading.
+func splitQualifiedName(name string) []qualifiedName {
+	if pkgPath, names, ok := strings.Cut(name, "#"); ok {
+		ifaceName, methodName, _ := strings.Cut(names, ".")
+		return []qualifiedName{{pkgPath, ifaceName, methodName}}
+	}
+
+	var readings []qualifiedName
+	for i := strings.LastIndex(name, "/") + 1; i < len(name); i++ {
+		if strings.HasPrefix(name[i:], "...") {
+			i += len("...") - 1
+			continue
+		}
+		if name[i] != '.' {
+			continue
+		}
+		// Identifiers have no dots, so the names are "Iface" or "Iface.Method"
+		names := name[i+1:]
+		if strings.Count(names, ".") > 1 || names == "" {
+			continue
+		}
+		ifaceName, methodName, _ := strings.Cut(names, ".")
+		readings = append(readings, qualifiedName{name[:i], ifaceName, methodName})
+	}
+	return readings
+}
+
+// MatchName checks if an identifier matches a glob pattern like "*Handler",
+// the syntax of interface and method names in contracts and severity rules
+func MatchName(pattern, name string) bool {
+	matched, _ := doublestar.Match(pattern, name)
+	return matched
+}
+
+// ValidNamePattern checks the syntax of a glob pattern for MatchName
+func ValidNamePattern(pattern string) bool {
+	return doublestar.ValidatePattern(pattern)
+}
+
+// MatchImportPath checks if an import path matches a go tool style pattern,
+// where "..." matches any string and "x/..." also matches "x" itself
+func MatchImportPath(pattern, pkgPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok && pkgPath == prefix {
+		return true
+	}
+
+	parts := strings.Split(pattern, "...")
+	if len(parts) == 1 {
+		return pattern == pkgPath
+	}
+	if !strings.HasPrefix(pkgPath, parts[0]) {
+		return false
+	}
+	pkgPath = pkgPath[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(pkgPath, part)
+		if i < 0 {
+			return false
+		}
+		pkgPath = pkgPath[i+len(part):]
+	}
+	return strings.HasSuffix(pkgPath, parts[len(parts)-1])
+}
+
+// ShouldReport checks if interfaces with the given visibility in the package are reported in the current mode
+func (c *Config) ShouldReport(pkgPath string, exported bool) bool {
+	switch c.Mode {
+	case ModeExported:
+		return exported
+	case ModeUnexported:
+		return !exported
+	case ModeInternal:
+		return isInternalPackage(pkgPath)
+	default:
+		return true
+	}
+}
+
+// isInternalPackage checks if the import path has an internal element
+func isInternalPackage(pkgPath string) bool {
+	return pkgPath == "internal" ||
+		strings.HasPrefix(pkgPath, "internal/") ||
+		strings.HasSuffix(pkgPath, "/internal") ||
+		strings.Contains(pkgPath, "/internal/")
+}
+
+// SeverityFor returns the severity of a finding in the given interface
+func (c *Config) SeverityFor(pkgPath, ifaceName string, exported bool) Severity {
+	for _, rule := range c.SeverityRules {
+		if rule.matches(pkgPath, ifaceName, exported) {
+			return rule.Level
+		}
+	}
+	if c.Severity == "" {
+		return SeverityError
+	}
+	return c.Severity
+}
+
+// TestOnlySeverityFor returns the severity of a method used only from test files.
+// Interfaces whose findings are off stay off.
+func (c *Config) TestOnlySeverityFor(pkgPath, ifaceName string, exported bool) Severity {
+	if c.SeverityFor(pkgPath, ifaceName, exported) == SeverityOff {
+		return SeverityOff
+	}
+	if c.TestOnlySeverity == "" {
+		return SeverityWarning
+	}
+	return c.TestOnlySeverity
+}
+
+// matches checks if the rule selects the given interface
+func (r *SeverityRule) matches(pkgPath, ifaceName string, exported bool) bool {
+	if r.Exported != nil && *r.Exported != exported {
+		return false
+	}
+	return matchAny(r.Packages, pkgPath, MatchImportPath) && matchAny(r.Interfaces, ifaceName, MatchName)
+}
+
+// matchAny checks if the value matches any of the patterns, an empty list matches everything
+func matchAny(patterns []string, value string, match func(pattern, value string) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks configuration values that yaml cannot check by itself
+func (c *Config) validate() error {
+	if c.Mode != "" && !c.Mode.valid() {
+		return fmt.Errorf("invalid mode %q", c.Mode)
+	}
+	if c.Severity != "" && !c.Severity.valid() {
+		return fmt.Errorf("invalid severity %q", c.Severity)
+	}
+	if c.TestOnlySeverity != "" && !c.TestOnlySeverity.valid() {
+		return fmt.Errorf("invalid test-only-severity %q", c.TestOnlySeverity)
+	}
+	if err := validateImportPathPatterns("ignore-packages", c.IgnorePackages); err != nil {
+		return err
+	}
+	for i, rule := range c.SeverityRules {
+		if !rule.Level.valid() {
+			return fmt.Errorf("severity-rules[%d]: invalid level %q", i, rule.Level)
+		}
+		if err := validateImportPathPatterns(fmt.Sprintf("severity-rules[%d].packages", i), rule.Packages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateImportPathPatterns rejects file globs in import path patterns,
+// which use "..." like go tool patterns and would otherwise never match
+func validateImportPathPatterns(key string, patterns []string) error {
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, "*?[") {
+			return fmt.Errorf("%s: invalid import path pattern %q, use \"...\" to match any string", key, pattern)
+		}
+	}
+	return nil
+}
+
+// valid checks if the severity is one of the known levels
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+	return false
+}
+
+// valid checks if the mode is one of the known modes
+func (m Mode) valid() bool {
+	switch m {
+	case ModeAll, ModeExported, ModeUnexported, ModeInternal:
+		return true
+	}
+	return false
+}
+
+// SetMode sets the mode, e.g. from a command line flag
+func (c *Config) SetMode(mode string) error {
+	m := Mode(mode)
+	if !m.valid() {
+		return fmt.Errorf("invalid mode %q, want one of: all, exported, unexported, internal", mode)
+	}
+	c.Mode = m
+	return nil
+}
+
 // LoadConfig loads configuration from a file or returns default configuration
 func LoadConfig(configPath string) (*Config, error) {
 	// If path is not specified, look in standard locations
 	if configPath == "" {
-		configPath = findConfigFile()
+		configPath = findConfigFile(".")
 	}
 
 	// If file is not found, use default configuration
@@ -101,6 +421,44 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, err
 	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", configPath, err)
+	}
+
+	return config, nil
+}
+
+// LoadConfigFrom loads the configuration file found in dir, like LoadConfig does
+// in the current directory, or returns the default configuration
+func LoadConfigFrom(dir string) (*Config, error) {
+	configPath := findConfigFile(dir)
+	if configPath == "" {
+		return defaultConfig(), nil
+	}
+	return LoadConfig(configPath)
+}
+
+// ParseSettings builds the configuration from settings decoded by another tool,
+// like the linter settings in .golangci.yml, using the keys of the configuration file.
+// Unknown keys are rejected, so that typos do not silently disable settings.
+func ParseSettings(settings any) (*Config, error) {
+	config := defaultConfig()
+	if settings == nil {
+		return config, nil
+	}
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 
 	return config, nil
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -156,6 +157,33 @@ func TestLoadConfig_ExplicitConfigPath(t *testing.T) {
 	}
 }
 
+func TestLoadConfigFrom(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("mode: internal\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, ".config", "unused-interface-methods.yml"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigFrom(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() error = %v", err)
+	}
+	if cfg.Mode != ModeInternal {
+		t.Errorf("LoadConfigFrom() mode = %q, want %q", cfg.Mode, ModeInternal)
+	}
+
+	cfg, err = LoadConfigFrom(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() without config error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, defaultConfig()) {
+		t.Errorf("LoadConfigFrom() without config = %v, want %v", cfg, defaultConfig())
+	}
+}
+
 func TestLoadConfig_PermissionDenied(t *testing.T) {
 	// Skip this test on Windows as permission handling is different
 	if runtime.GOOS == "windows" {
@@ -205,3 +233,323 @@ func TestLoadConfig_PermissionDenied(t *testing.T) {
 	// Restore permissions for cleanup
 	os.Chmod(noAccessDir, 0700)
 }
+
+func TestSeverityFor(t *testing.T) {
+	exported := true
+	unexported := false
+	cfg := &Config{
+		Severity: SeverityWarning,
+		SeverityRules: []SeverityRule{
+			{Packages: []string{"github.com/acme/svc/internal/..."}, Level: SeverityError},
+			{Interfaces: []string{"*Handler"}, Level: SeverityInfo},
+			{Exported: &unexported, Level: SeverityOff},
+			{Packages: []string{"github.com/acme/svc/api"}, Exported: &exported, Level: SeverityError},
+		},
+	}
+
+	testCases := []struct {
+		pkgPath   string
+		ifaceName string
+		exported  bool
+		want      Severity
+	}{
+		{"github.com/acme/svc/internal/store", "store", false, SeverityError}, // first rule wins
+		{"github.com/acme/svc/web", "EventHandler", true, SeverityInfo},       // interface pattern
+		{"github.com/acme/svc/web", "cache", false, SeverityOff},              // unexported
+		{"github.com/acme/svc/api", "Client", true, SeverityError},            // package and exported
+		{"github.com/acme/svc/web", "Client", true, SeverityWarning},          // default
+	}
+
+	for _, tc := range testCases {
+		got := cfg.SeverityFor(tc.pkgPath, tc.ifaceName, tc.exported)
+		if got != tc.want {
+			t.Errorf("SeverityFor(%s, %s, %v) = %v, want %v", tc.pkgPath, tc.ifaceName, tc.exported, got, tc.want)
+		}
+	}
+
+	if got := defaultConfig().SeverityFor("any", "Any", true); got != SeverityError {
+		t.Errorf("SeverityFor() with default config = %v, want %v", got, SeverityError)
+	}
+}
+
+func TestLoadConfig_InvalidSeverity(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testCases := []string{
+		"severity: fatal",
+		"severity-rules:\n  - interfaces: [\"*\"]\n    level: loud",
+		"severity-rules:\n  - interfaces: [\"*\"]",
+	}
+
+	for i, content := range testCases {
+		path := filepath.Join(tmpDir, fmt.Sprintf("config%d.yml", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%q) error = nil, want error for invalid severity", content)
+		}
+	}
+}
+
+func TestShouldReport(t *testing.T) {
+	testCases := []struct {
+		mode     Mode
+		pkgPath  string
+		exported bool
+		want     bool
+	}{
+		{"", "github.com/acme/svc", true, true},
+		{ModeAll, "github.com/acme/svc", false, true},
+		{ModeExported, "github.com/acme/svc", true, true},
+		{ModeExported, "github.com/acme/svc", false, false},
+		{ModeUnexported, "github.com/acme/svc", true, false},
+		{ModeUnexported, "github.com/acme/svc", false, true},
+		{ModeInternal, "github.com/acme/svc/internal/store", true, true},
+		{ModeInternal, "github.com/acme/svc/internal", false, true},
+		{ModeInternal, "internal/store", true, true},
+		{ModeInternal, "github.com/acme/svc/internals", true, false},
+		{ModeInternal, "github.com/acme/svc", false, false},
+	}
+
+	for _, tc := range testCases {
+		cfg := &Config{Mode: tc.mode}
+		got := cfg.ShouldReport(tc.pkgPath, tc.exported)
+		if got != tc.want {
+			t.Errorf("ShouldReport(%s, %v) in mode %q = %v, want %v", tc.pkgPath, tc.exported, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	cfg := defaultConfig()
+	if err := cfg.SetMode("unexported"); err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+	if cfg.Mode != ModeUnexported {
+		t.Errorf("Mode = %v, want %v", cfg.Mode, ModeUnexported)
+	}
+	if err := cfg.SetMode("public"); err == nil {
+		t.Error("SetMode() error = nil, want error for unknown mode")
+	}
+}
+
+func TestShouldIgnorePackage(t *testing.T) {
+	cfg := &Config{
+		IgnorePackages: []string{
+			"github.com/acme/svc/internal/...",
+			"github.com/acme/.../gen",
+			"example.com/exact",
+		},
+	}
+
+	testCases := []struct {
+		pkgPath string
+		want    bool
+	}{
+		{"github.com/acme/svc/internal", true},           // "x/..." matches x itself
+		{"github.com/acme/svc/internal/store", true},     // subpackage
+		{"github.com/acme/svc/internal/store/sql", true}, // nested subpackage
+		{"github.com/acme/svc/internalx", false},         // not a subpackage
+		{"github.com/acme/api/gen", true},                // "..." in the middle
+		{"github.com/acme/api/v2/gen", true},             // "..." spans slashes
+		{"github.com/acme/api/generated", false},         // suffix must match
+		{"example.com/exact", true},                      // no wildcard
+		{"example.com/exact/sub", false},                 // no wildcard
+		{"github.com/other/svc/internal/store", false},   // different module
+		{"github.com/acme/svc", false},                   // parent package
+	}
+
+	for _, tc := range testCases {
+		got := cfg.ShouldIgnorePackage(tc.pkgPath)
+		if got != tc.want {
+			t.Errorf("ShouldIgnorePackage(%s) = %v, want %v", tc.pkgPath, got, tc.want)
+		}
+	}
+}
+
+func TestShouldIgnoreMethod(t *testing.T) {
+	cfg := &Config{
+		IgnoreInterfaces: []string{
+			"github.com/acme/svc/store.Store",
+			"github.com/acme/svc/api.Client.Close",
+			"github.com/acme/.../events.*Handler",
+			"main.Plugin",
+			"gopkg.in/yaml.v3.Node",
+			"gopkg.in/check.v1.Checker.Info",
+			"example.com.Iface",
+			"github.com/acme/legacy/....Store",
+			"github.com/acme/dotted.v2#Codec.Encode",
+		},
+	}
+
+	testCases := []struct {
+		pkgPath    string
+		ifaceName  string
+		methodName string
+		want       bool
+	}{
+		{"github.com/acme/svc/store", "Store", "", true},           // whole interface
+		{"github.com/acme/svc/store", "Store", "Get", true},        // method of ignored interface
+		{"github.com/acme/svc/store", "Cache", "Get", false},       // other interface
+		{"github.com/acme/svc/api", "Client", "", false},           // only a method is ignored
+		{"github.com/acme/svc/api", "Client", "Close", true},       // ignored method
+		{"github.com/acme/svc/api", "Client", "Open", false},       // other method
+		{"github.com/acme/svc/events", "EventHandler", "On", true}, // wildcards
+		{"github.com/acme/svc/events", "Emitter", "On", false},     // name pattern
+		{"main", "Plugin", "Init", true},                           // package without slashes
+		{"github.com/other/main", "Plugin", "Init", false},         // import path, not package name
+		{"gopkg.in/yaml.v3", "Node", "Decode", true},               // versioned package
+		{"gopkg.in/yaml", "v3", "Node", true},                      // other reading of the same name
+		{"gopkg.in/yaml.v3", "Marshaler", "", false},               // other interface
+		{"gopkg.in/check.v1", "Checker", "Info", true},             // versioned package, method
+		{"gopkg.in/check.v1", "Checker", "Check", false},           // versioned package, other method
+		{"example.com", "Iface", "Get", true},                      // dotted host without slashes
+		{"github.com/acme/legacy/db", "Store", "Get", true},        // "..." followed by the interface
+		{"github.com/acme/legacy", "Store", "Get", true},           // "x/..." matches x itself
+		{"github.com/acme/dotted.v2", "Codec", "Encode", true},     // explicit separator
+		{"github.com/acme/dotted.v2", "Codec", "Decode", false},    // explicit separator, other method
+		{"github.com/acme/dotted", "v2", "Codec", false},           // explicit separator has one reading
+	}
+
+	for _, tc := range testCases {
+		got := cfg.ShouldIgnoreMethod(tc.pkgPath, tc.ifaceName, tc.methodName)
+		if got != tc.want {
+			t.Errorf("ShouldIgnoreMethod(%s, %s, %s) = %v, want %v", tc.pkgPath, tc.ifaceName, tc.methodName, got, tc.want)
+		}
+	}
+
+	if !cfg.ShouldIgnoreInterface("github.com/acme/svc/store", "Store") {
+		t.Error("ShouldIgnoreInterface(Store) = false, want true")
+	}
+	if cfg.ShouldIgnoreInterface("github.com/acme/svc/api", "Client") {
+		t.Error("ShouldIgnoreInterface(Client) = true, want false")
+	}
+}
+
+func TestSplitQualifiedName(t *testing.T) {
+	testCases := []struct {
+		name string
+		want []qualifiedName
+	}{
+		{"main.Plugin", []qualifiedName{{"main", "Plugin", ""}}},
+		{"github.com/acme/svc/api.Client.Close", []qualifiedName{
+			{"github.com/acme/svc/api", "Client", "Close"},
+			{"github.com/acme/svc/api.Client", "Close", ""},
+		}},
+		{"gopkg.in/yaml.v3.Node", []qualifiedName{
+			{"gopkg.in/yaml", "v3", "Node"},
+			{"gopkg.in/yaml.v3", "Node", ""},
+		}},
+		{"gopkg.in/yaml.v3.Node.Decode", []qualifiedName{
+			{"gopkg.in/yaml.v3", "Node", "Decode"},
+			{"gopkg.in/yaml.v3.Node", "Decode", ""},
+		}},
+		{"example.com.Iface", []qualifiedName{
+			{"example", "com", "Iface"},
+			{"example.com", "Iface", ""},
+		}},
+		{"github.com/acme/svc/....Store", []qualifiedName{{"github.com/acme/svc/...", "Store", ""}}},
+		{"github.com/acme/.../events.*Handler", []qualifiedName{{"github.com/acme/.../events", "*Handler", ""}}},
+		{"gopkg.in/yaml.v3#Node.Decode", []qualifiedName{{"gopkg.in/yaml.v3", "Node", "Decode"}}},
+		{"gopkg.in/yaml.v3#Node", []qualifiedName{{"gopkg.in/yaml.v3", "Node", ""}}},
+		{"github.com/acme/svc", nil},
+	}
+
+	for _, tc := range testCases {
+		if got := splitQualifiedName(tc.name); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("splitQualifiedName(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestUsageFiles(t *testing.T) {
+	cfg := &Config{IgnoreUsage: []string{"**/*_mock.go", "examples/**"}}
+
+	testCases := []struct {
+		path        string
+		ignoreUsage bool
+		testFile    bool
+	}{
+		{"service/user_mock.go", true, false},
+		{filepath.Join("examples", "basic", "main.go"), true, false},
+		{"service/user_test.go", false, true},
+		{"user_test.go", false, true},
+		{"service/user.go", false, false},
+	}
+
+	for _, tc := range testCases {
+		if got := cfg.ShouldIgnoreUsage(tc.path); got != tc.ignoreUsage {
+			t.Errorf("ShouldIgnoreUsage(%s) = %v, want %v", tc.path, got, tc.ignoreUsage)
+		}
+		if got := cfg.IsTestFile(tc.path); got != tc.testFile {
+			t.Errorf("IsTestFile(%s) = %v, want %v", tc.path, got, tc.testFile)
+		}
+	}
+
+	cfg.TestFiles = []string{"testing/**"}
+	if cfg.IsTestFile("service/user_test.go") {
+		t.Error("IsTestFile() with custom patterns matched the default pattern")
+	}
+	if !cfg.IsTestFile("testing/helpers.go") {
+		t.Error("IsTestFile() did not match custom pattern")
+	}
+}
+
+func TestTestOnlySeverityFor(t *testing.T) {
+	cfg := &Config{
+		SeverityRules: []SeverityRule{
+			{Packages: []string{"github.com/acme/svc/api"}, Level: SeverityOff},
+		},
+	}
+	if got := cfg.TestOnlySeverityFor("github.com/acme/svc/store", "Store", true); got != SeverityWarning {
+		t.Errorf("TestOnlySeverityFor() = %v, want %v", got, SeverityWarning)
+	}
+	if got := cfg.TestOnlySeverityFor("github.com/acme/svc/api", "Client", true); got != SeverityOff {
+		t.Errorf("TestOnlySeverityFor() = %v, want %v", got, SeverityOff)
+	}
+	cfg.TestOnlySeverity = SeverityError
+	if got := cfg.TestOnlySeverityFor("github.com/acme/svc/store", "Store", true); got != SeverityError {
+		t.Errorf("TestOnlySeverityFor() = %v, want %v", got, SeverityError)
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	settings := map[string]any{
+		"ignore":   []any{"**/mocks/**"},
+		"mode":     "internal",
+		"severity": "warning",
+		"severity-rules": []any{
+			map[string]any{"packages": []any{"example.com/api/..."}, "level": "off"},
+		},
+	}
+	cfg, err := ParseSettings(settings)
+	if err != nil {
+		t.Fatalf("ParseSettings() error = %v", err)
+	}
+	want := &Config{
+		Ignore:   []string{"**/mocks/**"},
+		Mode:     ModeInternal,
+		Severity: SeverityWarning,
+		SeverityRules: []SeverityRule{
+			{Packages: []string{"example.com/api/..."}, Level: SeverityOff},
+		},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ParseSettings() = %+v, want %+v", cfg, want)
+	}
+
+	if cfg, err := ParseSettings(nil); err != nil || !reflect.DeepEqual(cfg, defaultConfig()) {
+		t.Errorf("ParseSettings(nil) = %+v, %v, want default configuration", cfg, err)
+	}
+	for _, invalid := range []map[string]any{
+		{"ignores": []any{"x"}},
+		{"mode": "public"},
+		{"ignore-packages": []any{"example.com/**"}},
+		{"severity-rules": []any{map[string]any{"packages": []any{"example.com/*/api"}, "level": "off"}}},
+	} {
+		if _, err := ParseSettings(invalid); err == nil {
+			t.Errorf("ParseSettings(%v) error = nil, want error", invalid)
+		}
+	}
+}
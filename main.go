@@ -22,5 +22,30 @@ func main() {
 			return
 		}
 	}
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "contracts":
+			analizer.RunContracts()
+			return
+		case "unused-rpcs":
+			analizer.RunUnusedRPCs()
+			return
+		case "explain":
+			analizer.RunExplain()
+			return
+		case "stats":
+			analizer.RunStats()
+			return
+		case "report":
+			analizer.RunReport()
+			return
+		case "graph":
+			analizer.RunGraph()
+			return
+		case "lsp":
+			analizer.RunLSP()
+			return
+		}
+	}
 	analizer.Run()
 }
@@ -0,0 +1,54 @@
+// Package plugin registers the analyzer as a golangci-lint module plugin.
+//
+// Settings of the linter in .golangci.yml use the keys of the configuration file:
+//
+//	linters:
+//	  enable:
+//	    - unusedinterfacemethods
+//	  settings:
+//	    custom:
+//	      unusedinterfacemethods:
+//	        type: module
+//	        settings:
+//	          mode: internal
+//	          ignore:
+//	            - "**/mocks/**"
+package plugin
+
+import (
+	"github.com/golangci/plugin-module-register/register"
+	"github.com/unused-interface-methods/unused-interface-methods/internal/analizer"
+	"github.com/unused-interface-methods/unused-interface-methods/internal/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Name is the name of the linter in .golangci.yml
+const Name = "unusedinterfacemethods"
+
+func init() {
+	register.Plugin(Name, New)
+}
+
+// unusedInterfaceMethods is the golangci-lint plugin of the analyzer
+type unusedInterfaceMethods struct {
+	cfg *config.Config
+}
+
+// New creates the plugin from the linter settings
+func New(settings any) (register.LinterPlugin, error) {
+	cfg, err := config.ParseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	return &unusedInterfaceMethods{cfg: cfg}, nil
+}
+
+// BuildAnalyzers returns the analyzer configured with the linter settings
+func (p *unusedInterfaceMethods) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	return []*analysis.Analyzer{analizer.NewAnalyzer(p.cfg)}, nil
+}
+
+// GetLoadMode returns the load mode of the analyzer, which needs type information
+func (p *unusedInterfaceMethods) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/golangci/plugin-module-register/register"
+)
+
+func TestPlugin(t *testing.T) {
+	newPlugin, err := register.GetPlugin(Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := newPlugin(map[string]any{"mode": "internal", "ignore": []any{"**/mocks/**"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	analyzers, err := p.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("BuildAnalyzers() error = %v", err)
+	}
+	if len(analyzers) != 1 || analyzers[0].Name != "unused_interface_methods" {
+		t.Errorf("BuildAnalyzers() = %v, want the unused_interface_methods analyzer", analyzers)
+	}
+	other, err := newPlugin(nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	otherAnalyzers, err := other.BuildAnalyzers()
+	if err != nil {
+		t.Fatalf("BuildAnalyzers() error = %v", err)
+	}
+	if otherAnalyzers[0] == analyzers[0] {
+		t.Error("BuildAnalyzers() of two plugins returned the same analyzer, want one per configuration")
+	}
+	if got := p.GetLoadMode(); got != register.LoadModeTypesInfo {
+		t.Errorf("GetLoadMode() = %q, want %q", got, register.LoadModeTypesInfo)
+	}
+
+	if _, err := newPlugin(map[string]any{"severity": "fatal"}); err == nil {
+		t.Error("New() with invalid severity error = nil, want error")
+	}
+}